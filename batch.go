@@ -0,0 +1,164 @@
+package smalltime
+
+import "time"
+
+const secondsPerDay = 86400
+const microsecondsPerDay = secondsPerDay * 1000000
+const nanosecondsPerDay = secondsPerDay * 1000000000
+
+// Fields holds decoded date & time fields in struct-of-arrays form, for
+// columnar processing. The decode functions resize each slice to the number of
+// values decoded, reusing the existing capacity where possible.
+type Fields struct {
+	Year       []int
+	Month      []int
+	Day        []int
+	Hour       []int
+	Minute     []int
+	Second     []int
+	Nanosecond []int
+}
+
+func resizeInts(ints []int, length int) []int {
+	if cap(ints) < length {
+		return make([]int, length)
+	}
+	return ints[:length]
+}
+
+func (f *Fields) resize(length int) {
+	f.Year = resizeInts(f.Year, length)
+	f.Month = resizeInts(f.Month, length)
+	f.Day = resizeInts(f.Day, length)
+	f.Hour = resizeInts(f.Hour, length)
+	f.Minute = resizeInts(f.Minute, length)
+	f.Second = resizeInts(f.Second, length)
+	f.Nanosecond = resizeInts(f.Nanosecond, length)
+}
+
+func minLength(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func smalltimeFromUnixMicros(unixMicros int64) Smalltime {
+	days, micros := floorDivMod(unixMicros, microsecondsPerDay)
+	year, month, day := civilFromDays(days)
+	seconds := micros / 1000000
+	return Smalltime(year)<<bitshiftYear |
+		Smalltime(month)<<bitshiftMonth |
+		Smalltime(day)<<bitshiftDay |
+		Smalltime(seconds/3600)<<bitshiftHour |
+		Smalltime(seconds/60%60)<<bitshiftMinute |
+		Smalltime(seconds%60)<<bitshiftSecond |
+		Smalltime(micros%1000000)
+}
+
+func nanotimeFromUnixNanos(unixNanos int64) Nanotime {
+	days, nanos := floorDivMod(unixNanos, nanosecondsPerDay)
+	year, month, day := civilFromDays(days)
+	seconds := nanos / 1000000000
+	return Nanotime(year-zeroYearNanotime)<<bitshiftYearNanotime |
+		Nanotime(month)<<bitshiftMonthNanotime |
+		Nanotime(day)<<bitshiftDayNanotime |
+		Nanotime(seconds/3600)<<bitshiftHourNanotime |
+		Nanotime(seconds/60%60)<<bitshiftMinuteNanotime |
+		Nanotime(seconds%60)<<bitshiftSecondNanotime |
+		Nanotime(nanos%1000000000)
+}
+
+// EncodeSmalltimes converts Unix times in microseconds to Smalltimes, returning
+// the number of values converted (the shorter of dst and src).
+func EncodeSmalltimes(dst []Smalltime, src []int64) int {
+	count := minLength(len(dst), len(src))
+	for i, unixMicros := range src[:count] {
+		dst[i] = smalltimeFromUnixMicros(unixMicros)
+	}
+	return count
+}
+
+// EncodeNanotimes converts Unix times in nanoseconds to Nanotimes, returning
+// the number of values converted (the shorter of dst and src).
+func EncodeNanotimes(dst []Nanotime, src []int64) int {
+	count := minLength(len(dst), len(src))
+	for i, unixNanos := range src[:count] {
+		dst[i] = nanotimeFromUnixNanos(unixNanos)
+	}
+	return count
+}
+
+// EncodeSmalltimesFromTimes converts times to Smalltimes, returning the number
+// of values converted (the shorter of dst and src).
+func EncodeSmalltimesFromTimes(dst []Smalltime, src []time.Time) int {
+	count := minLength(len(dst), len(src))
+	for i, t := range src[:count] {
+		dst[i] = smalltimeFromUnixMicros(t.Unix()*1000000 + int64(t.Nanosecond()/1000))
+	}
+	return count
+}
+
+// EncodeNanotimesFromTimes converts times to Nanotimes, returning the number of
+// values converted (the shorter of dst and src).
+func EncodeNanotimesFromTimes(dst []Nanotime, src []time.Time) int {
+	count := minLength(len(dst), len(src))
+	for i, t := range src[:count] {
+		dst[i] = nanotimeFromUnixNanos(t.Unix()*1000000000 + int64(t.Nanosecond()))
+	}
+	return count
+}
+
+// DecodeSmalltimes converts Smalltimes to Unix times in microseconds, returning
+// the number of values converted (the shorter of dst and src). A leap second
+// is treated as the first second of the following minute, like time.Date does.
+func DecodeSmalltimes(dst []int64, src []Smalltime) int {
+	count := minLength(len(dst), len(src))
+	for i, t := range src[:count] {
+		days := daysFromCivil(t.Year(), t.Month(), t.Day())
+		seconds := int64(t.Hour()*3600 + t.Minute()*60 + t.Second())
+		dst[i] = days*microsecondsPerDay + seconds*1000000 + int64(t.Microsecond())
+	}
+	return count
+}
+
+// DecodeNanotimes converts Nanotimes to Unix times in nanoseconds, returning the
+// number of values converted (the shorter of dst and src). A leap second is
+// treated as the first second of the following minute, like time.Date does.
+func DecodeNanotimes(dst []int64, src []Nanotime) int {
+	count := minLength(len(dst), len(src))
+	for i, t := range src[:count] {
+		days := daysFromCivil(t.Year(), t.Month(), t.Day())
+		seconds := int64(t.Hour()*3600 + t.Minute()*60 + t.Second())
+		dst[i] = days*nanosecondsPerDay + seconds*1000000000 + int64(t.Nanosecond())
+	}
+	return count
+}
+
+// DecodeSmalltimeFields extracts the fields of each Smalltime into dst.
+func DecodeSmalltimeFields(dst *Fields, src []Smalltime) {
+	dst.resize(len(src))
+	for i, t := range src {
+		dst.Year[i] = t.Year()
+		dst.Month[i] = t.Month()
+		dst.Day[i] = t.Day()
+		dst.Hour[i] = t.Hour()
+		dst.Minute[i] = t.Minute()
+		dst.Second[i] = t.Second()
+		dst.Nanosecond[i] = t.Microsecond() * 1000
+	}
+}
+
+// DecodeNanotimeFields extracts the fields of each Nanotime into dst.
+func DecodeNanotimeFields(dst *Fields, src []Nanotime) {
+	dst.resize(len(src))
+	for i, t := range src {
+		dst.Year[i] = t.Year()
+		dst.Month[i] = t.Month()
+		dst.Day[i] = t.Day()
+		dst.Hour[i] = t.Hour()
+		dst.Minute[i] = t.Minute()
+		dst.Second[i] = t.Second()
+		dst.Nanosecond[i] = t.Nanosecond()
+	}
+}
@@ -0,0 +1,208 @@
+package smalltime
+
+import "math/rand"
+import "testing"
+import "time"
+
+// Unix microseconds from year -100000 to year 100000
+const minTestUnixMicros = -3217830796800000000
+const maxTestUnixMicros = 3093527980800000000
+
+// Unix nanoseconds from 1970 to 2225
+const maxTestUnixNanos = 8078326400000000000
+
+func randomUnixMicros(random *rand.Rand, count int) []int64 {
+	values := make([]int64, count)
+	for i := range values {
+		values[i] = minTestUnixMicros + random.Int63n(maxTestUnixMicros-minTestUnixMicros)
+	}
+	return values
+}
+
+func randomUnixNanos(random *rand.Rand, count int) []int64 {
+	values := make([]int64, count)
+	for i := range values {
+		values[i] = random.Int63n(maxTestUnixNanos)
+	}
+	return values
+}
+
+func TestEncodeDecodeSmalltimes(t *testing.T) {
+	src := append(randomUnixMicros(rand.New(rand.NewSource(1)), 10000), 0, -1, 1, 951782400000000)
+	encoded := make([]Smalltime, len(src))
+	if count := EncodeSmalltimes(encoded, src); count != len(src) {
+		t.Errorf("Expected %v values to be encoded but got %v", len(src), count)
+	}
+	for i, unixMicros := range src {
+		expected := SmalltimeFromTime(time.UnixMicro(unixMicros))
+		if encoded[i] != expected {
+			t.Errorf("Expected %v to encode to %016x but got %016x", unixMicros, expected, encoded[i])
+		}
+	}
+
+	decoded := make([]int64, len(encoded))
+	DecodeSmalltimes(decoded, encoded)
+	for i, unixMicros := range src {
+		if decoded[i] != unixMicros {
+			t.Errorf("Expected %016x to decode to %v but got %v", encoded[i], unixMicros, decoded[i])
+		}
+	}
+}
+
+func TestEncodeDecodeNanotimes(t *testing.T) {
+	src := append(randomUnixNanos(rand.New(rand.NewSource(1)), 10000), 0, 1, 951782400000000000)
+	encoded := make([]Nanotime, len(src))
+	if count := EncodeNanotimes(encoded, src); count != len(src) {
+		t.Errorf("Expected %v values to be encoded but got %v", len(src), count)
+	}
+	for i, unixNanos := range src {
+		expected := NanotimeFromTime(time.Unix(0, unixNanos))
+		if encoded[i] != expected {
+			t.Errorf("Expected %v to encode to %016x but got %016x", unixNanos, expected, encoded[i])
+		}
+	}
+
+	decoded := make([]int64, len(encoded))
+	DecodeNanotimes(decoded, encoded)
+	for i, unixNanos := range src {
+		if decoded[i] != unixNanos {
+			t.Errorf("Expected %016x to decode to %v but got %v", encoded[i], unixNanos, decoded[i])
+		}
+	}
+}
+
+func TestEncodeFromTimes(t *testing.T) {
+	location := time.FixedZone("test", -5*3600)
+	src := []time.Time{
+		time.Date(1999, 2, 15, 12, 8, 45, 10159122, time.UTC),
+		time.Date(2000, 2, 29, 23, 0, 0, 1, location),
+	}
+	smalltimes := make([]Smalltime, len(src))
+	nanotimes := make([]Nanotime, len(src))
+	EncodeSmalltimesFromTimes(smalltimes, src)
+	EncodeNanotimesFromTimes(nanotimes, src)
+	for i, t2 := range src {
+		if smalltimes[i] != SmalltimeFromTime(t2) {
+			t.Errorf("Expected %v to encode to %016x but got %016x", t2, SmalltimeFromTime(t2), smalltimes[i])
+		}
+		if nanotimes[i] != NanotimeFromTime(t2) {
+			t.Errorf("Expected %v to encode to %016x but got %016x", t2, NanotimeFromTime(t2), nanotimes[i])
+		}
+	}
+}
+
+func TestDecodeLeapSecond(t *testing.T) {
+	decoded := make([]int64, 2)
+	DecodeSmalltimes(decoded, []Smalltime{
+		NewSmalltime(2016, 12, 31, 23, 59, 60, 0),
+		NewSmalltime(2017, 1, 1, 0, 0, 0, 0),
+	})
+	if decoded[0] != decoded[1] {
+		t.Errorf("Expected leap second to decode like the following second, but got %v and %v", decoded[0], decoded[1])
+	}
+}
+
+func TestDecodeFields(t *testing.T) {
+	src := []Smalltime{
+		NewSmalltime(1999, 2, 15, 12, 8, 45, 9122),
+		NewSmalltime(-44, 3, 15, 0, 0, 60, 1),
+	}
+	fields := Fields{Year: make([]int, 0, 10)}
+	DecodeSmalltimeFields(&fields, src)
+	for i, v := range src {
+		if fields.Year[i] != v.Year() || fields.Month[i] != v.Month() || fields.Day[i] != v.Day() ||
+			fields.Hour[i] != v.Hour() || fields.Minute[i] != v.Minute() || fields.Second[i] != v.Second() ||
+			fields.Nanosecond[i] != v.Nanosecond() {
+			t.Errorf("Fields at index %v don't match %016x", i, v)
+		}
+	}
+
+	nanotimes := []Nanotime{NewNanotime(1999, 2, 15, 12, 8, 45, 10159122)}
+	DecodeNanotimeFields(&fields, nanotimes)
+	if len(fields.Year) != 1 || fields.Nanosecond[0] != 10159122 || fields.Day[0] != 15 {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+}
+
+func TestShortBatchDestination(t *testing.T) {
+	if count := EncodeSmalltimes(make([]Smalltime, 2), []int64{1, 2, 3}); count != 2 {
+		t.Errorf("Expected 2 values to be encoded but got %v", count)
+	}
+	if count := DecodeNanotimes(make([]int64, 3), []Nanotime{1}); count != 1 {
+		t.Errorf("Expected 1 value to be decoded but got %v", count)
+	}
+}
+
+const benchmarkBatchSize = 4096
+
+func BenchmarkEncodeSmalltimesLoop(b *testing.B) {
+	src := randomUnixMicros(rand.New(rand.NewSource(1)), benchmarkBatchSize)
+	dst := make([]Smalltime, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, unixMicros := range src {
+			dst[j] = SmalltimeFromTime(time.UnixMicro(unixMicros))
+		}
+	}
+}
+
+func BenchmarkEncodeSmalltimesBatch(b *testing.B) {
+	src := randomUnixMicros(rand.New(rand.NewSource(1)), benchmarkBatchSize)
+	dst := make([]Smalltime, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EncodeSmalltimes(dst, src)
+	}
+}
+
+func BenchmarkEncodeNanotimesLoop(b *testing.B) {
+	src := randomUnixNanos(rand.New(rand.NewSource(1)), benchmarkBatchSize)
+	dst := make([]Nanotime, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, unixNanos := range src {
+			dst[j] = NanotimeFromTime(time.Unix(0, unixNanos))
+		}
+	}
+}
+
+func BenchmarkEncodeNanotimesBatch(b *testing.B) {
+	src := randomUnixNanos(rand.New(rand.NewSource(1)), benchmarkBatchSize)
+	dst := make([]Nanotime, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EncodeNanotimes(dst, src)
+	}
+}
+
+func BenchmarkDecodeSmalltimesLoop(b *testing.B) {
+	src := make([]Smalltime, benchmarkBatchSize)
+	EncodeSmalltimes(src, randomUnixMicros(rand.New(rand.NewSource(1)), benchmarkBatchSize))
+	dst := make([]int64, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, t := range src {
+			dst[j] = t.AsTime().UnixMicro()
+		}
+	}
+}
+
+func BenchmarkDecodeSmalltimesBatch(b *testing.B) {
+	src := make([]Smalltime, benchmarkBatchSize)
+	EncodeSmalltimes(src, randomUnixMicros(rand.New(rand.NewSource(1)), benchmarkBatchSize))
+	dst := make([]int64, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeSmalltimes(dst, src)
+	}
+}
+
+func BenchmarkDecodeSmalltimeFields(b *testing.B) {
+	src := make([]Smalltime, benchmarkBatchSize)
+	EncodeSmalltimes(src, randomUnixMicros(rand.New(rand.NewSource(1)), benchmarkBatchSize))
+	var fields Fields
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeSmalltimeFields(&fields, src)
+	}
+}
@@ -0,0 +1,158 @@
+// Command smalltime-vectors generates the shared test vector file
+// (testdata/vectors.json) that implementations in other languages can use to
+// check conformance with the smalltime and nanotime specifications.
+//
+// Usage:
+//
+//	smalltime-vectors [-o file]
+//
+// Each vector gives the raw encoding, the decoded fields, whether the fields
+// are all in range, and (for valid values) the ISO 8601 representation. The
+// subsecond field is in microseconds for smalltime and nanoseconds for
+// nanotime.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kstenerud/go-smalltime"
+)
+
+type vector struct {
+	Description string `json:"description"`
+	Format      string `json:"format"`
+	Raw         string `json:"raw"`
+	Year        int    `json:"year"`
+	Month       int    `json:"month"`
+	Day         int    `json:"day"`
+	Hour        int    `json:"hour"`
+	Minute      int    `json:"minute"`
+	Second      int    `json:"second"`
+	Subsecond   int    `json:"subsecond"`
+	Valid       bool   `json:"valid"`
+	ISO8601     string `json:"iso8601,omitempty"`
+}
+
+type vectorFile struct {
+	Comment string   `json:"comment"`
+	Vectors []vector `json:"vectors"`
+}
+
+type fields struct {
+	description                                       string
+	year, month, day, hour, minute, second, subsecond int
+}
+
+var smalltimeCases = []fields{
+	{"spec example", 1985, 10, 26, 8, 22, 16, 900142},
+	{"spec example, next day", 1985, 10, 27, 8, 22, 16, 900142},
+	{"spec example, previous minute", 1985, 10, 26, 8, 21, 16, 900142},
+	{"unix epoch", 1970, 1, 1, 0, 0, 0, 0},
+	{"year zero", 0, 1, 1, 0, 0, 0, 0},
+	{"last instant before year zero", -1, 12, 31, 23, 59, 59, 999999},
+	{"negative leap year (divisible by 4)", -4, 2, 29, 0, 0, 0, 0},
+	{"negative common year (divisible by 100)", -100, 2, 28, 0, 0, 0, 0},
+	{"negative leap year (divisible by 400)", -400, 2, 29, 0, 0, 0, 0},
+	{"minimum year", -131072, 1, 1, 0, 0, 0, 0},
+	{"maximum year with leap second and max subsecond", 131071, 12, 31, 23, 59, 60, 999999},
+	{"leap day (divisible by 400)", 2000, 2, 29, 12, 0, 0, 0},
+	{"leap day (divisible by 4)", 2004, 2, 29, 0, 0, 0, 0},
+	{"common year (divisible by 100)", 1900, 2, 28, 23, 59, 59, 0},
+	{"leap second", 2016, 12, 31, 23, 59, 60, 0},
+	{"max subsecond", 1999, 2, 15, 12, 8, 45, 999999},
+	{"last 4-digit year", 9999, 12, 31, 23, 59, 59, 999999},
+	{"first 5-digit year", 10000, 1, 1, 0, 0, 0, 0},
+	{"invalid: all zero", 0, 0, 0, 0, 0, 0, 0},
+	{"invalid: not a leap year", 2001, 2, 29, 0, 0, 0, 0},
+	{"invalid: not a leap year (divisible by 100)", 1900, 2, 29, 0, 0, 0, 0},
+	{"invalid: day 31 of a 30-day month", 2000, 4, 31, 0, 0, 0, 0},
+	{"invalid: month 13", 2000, 13, 1, 0, 0, 0, 0},
+	{"invalid: hour 24", 2000, 1, 1, 24, 0, 0, 0},
+	{"invalid: minute 60", 2000, 1, 1, 0, 60, 0, 0},
+	{"invalid: second 61", 2000, 1, 1, 0, 0, 61, 0},
+	{"invalid: subsecond overflow", 2000, 1, 1, 0, 0, 0, 1000000},
+}
+
+var nanotimeCases = []fields{
+	{"spec example", 1985, 10, 26, 8, 22, 16, 123900142},
+	{"spec example, next day", 1985, 10, 27, 8, 22, 16, 123900142},
+	{"spec example, previous minute", 1985, 10, 26, 8, 21, 16, 123900142},
+	{"minimum (unix epoch)", 1970, 1, 1, 0, 0, 0, 0},
+	{"maximum with leap second and max subsecond", 2225, 12, 31, 23, 59, 60, 999999999},
+	{"leap day (divisible by 400)", 2000, 2, 29, 12, 0, 0, 0},
+	{"leap day (divisible by 4)", 2004, 2, 29, 0, 0, 0, 0},
+	{"common year (divisible by 100)", 2100, 2, 28, 23, 59, 59, 0},
+	{"leap second", 2016, 12, 31, 23, 59, 60, 0},
+	{"max subsecond", 1999, 2, 15, 12, 8, 45, 999999999},
+	{"invalid: all zero", 1970, 0, 0, 0, 0, 0, 0},
+	{"invalid: not a leap year (divisible by 100)", 2100, 2, 29, 0, 0, 0, 0},
+	{"invalid: month 15", 2000, 15, 1, 0, 0, 0, 0},
+	{"invalid: hour 24", 2000, 1, 1, 24, 0, 0, 0},
+	{"invalid: second 61", 2000, 1, 1, 0, 0, 61, 0},
+	{"invalid: subsecond overflow", 2000, 1, 1, 0, 0, 0, 1000000000},
+}
+
+func newVector(format string, f fields, raw uint64, err error, iso string) vector {
+	v := vector{
+		Description: f.description,
+		Format:      format,
+		Raw:         fmt.Sprintf("0x%016x", raw),
+		Year:        f.year,
+		Month:       f.month,
+		Day:         f.day,
+		Hour:        f.hour,
+		Minute:      f.minute,
+		Second:      f.second,
+		Subsecond:   f.subsecond,
+		Valid:       err == nil,
+	}
+	if v.Valid {
+		v.ISO8601 = iso
+	}
+	return v
+}
+
+func generate() vectorFile {
+	file := vectorFile{
+		Comment: "Generated by cmd/smalltime-vectors. Subsecond is in microseconds for smalltime and nanoseconds for nanotime.",
+	}
+	for _, f := range smalltimeCases {
+		value := smalltime.NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.subsecond)
+		file.Vectors = append(file.Vectors, newVector("smalltime", f, uint64(value), value.Validate(), smalltime.Format(value)))
+	}
+	for _, f := range nanotimeCases {
+		value := smalltime.NewNanotime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.subsecond)
+		file.Vectors = append(file.Vectors, newVector("nanotime", f, uint64(value), value.Validate(), smalltime.Format(value)))
+	}
+	return file
+}
+
+func write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(generate())
+}
+
+func main() {
+	output := flag.String("o", "", "Write to this file instead of stdout")
+	flag.Parse()
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "smalltime-vectors: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := write(w); err != nil {
+		fmt.Fprintf(os.Stderr, "smalltime-vectors: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestCommittedVectorsUpToDate(t *testing.T) {
+	committed, err := os.ReadFile("../../testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generated bytes.Buffer
+	if err = write(&generated); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, generated.Bytes()) {
+		t.Errorf("testdata/vectors.json is out of date; run go generate")
+	}
+}
@@ -0,0 +1,470 @@
+// Command smalltime encodes, decodes, converts and validates smalltime and
+// nanotime values from the command line.
+//
+// Usage:
+//
+//	smalltime decode   [-nano] [value ...]
+//	smalltime encode   [-nano] [iso8601 | year month day hour minute second [subsecond]]
+//	smalltime now      [-nano]
+//	smalltime convert  -from format -to format [value ...]
+//	smalltime validate [-nano] [value ...]
+//
+// Raw values may be given in hex (with a 0x prefix) or decimal. Commands that
+// take values read them one per line from stdin when none are given on the
+// command line, making it easy to process a column of values in batch.
+//
+// Formats understood by convert are smalltime, nanotime, iso8601, unix,
+// unixmilli, unixmicro and unixnano. Use -- before a value that starts with a
+// minus sign so that it isn't mistaken for a flag.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/go-smalltime"
+)
+
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(c *context, args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"decode", "Show the fields and bit layout of raw values", runDecode},
+		{"encode", "Encode an ISO 8601 date or a list of fields as a raw value", runEncode},
+		{"now", "Print the current time as a raw value", runNow},
+		{"convert", "Convert values between formats", runConvert},
+		{"validate", "Check that raw values contain only in-range fields", runValidate},
+	}
+}
+
+type context struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	now    func() time.Time
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &context{stdin: stdin, stdout: stdout, stderr: stderr, now: time.Now}
+	return c.run(args)
+}
+
+func (c *context) run(args []string) int {
+	if len(args) == 0 {
+		c.usage()
+		return exitUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(c, args[1:])
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		c.usage()
+		return exitOK
+	}
+	fmt.Fprintf(c.stderr, "smalltime: unknown command %q\n", args[0])
+	c.usage()
+	return exitUsage
+}
+
+func (c *context) usage() {
+	fmt.Fprintf(c.stderr, "Usage: smalltime <command> [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-9v %v\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(c.stderr, "\nRun smalltime <command> -h for help on a command.\n")
+}
+
+func (c *context) newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: smalltime %v %v\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// Calls handle for each argument, or for each non-blank line of stdin if there
+// are no arguments. Returns exitInvalid if any call fails.
+func (c *context) forEachValue(args []string, handle func(value string) error) int {
+	result := exitOK
+	process := func(value string) {
+		if err := handle(value); err != nil {
+			fmt.Fprintf(c.stderr, "smalltime: %v\n", err)
+			result = exitInvalid
+		}
+	}
+
+	if len(args) > 0 {
+		for _, arg := range args {
+			process(arg)
+		}
+		return result
+	}
+
+	scanner := bufio.NewScanner(c.stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			process(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(c.stderr, "smalltime: %v\n", err)
+		return exitInvalid
+	}
+	return result
+}
+
+func parseRaw(str string) (uint64, error) {
+	var value uint64
+	var err error
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		value, err = strconv.ParseUint(str[2:], 16, 64)
+	} else if strings.HasPrefix(str, "-") {
+		var signed int64
+		signed, err = strconv.ParseInt(str, 10, 64)
+		value = uint64(signed)
+	} else {
+		value, err = strconv.ParseUint(str, 10, 64)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid hex or decimal value", str)
+	}
+	return value, nil
+}
+
+func formatRaw(value uint64) string {
+	return fmt.Sprintf("0x%016x", value)
+}
+
+// ============================================================================
+
+func runDecode(c *context, args []string) int {
+	flags := c.newFlagSet("decode", "[-nano] [value ...]")
+	nano := flags.Bool("nano", false, "Decode values as nanotime instead of smalltime")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	first := true
+	return c.forEachValue(flags.Args(), func(str string) error {
+		raw, err := parseRaw(str)
+		if err != nil {
+			return err
+		}
+		if !first {
+			fmt.Fprintln(c.stdout)
+		}
+		first = false
+
+		layout := smalltime.SmalltimeLayout()
+		iso, validateErr := formatSmalltime(smalltime.Smalltime(raw))
+		if *nano {
+			layout = smalltime.NanotimeLayout()
+			iso, validateErr = formatNanotime(smalltime.Nanotime(raw))
+		}
+		fmt.Fprintf(c.stdout, "raw:         %v\n", formatRaw(raw))
+		fmt.Fprintf(c.stdout, "iso8601:     %v\n", iso)
+		for _, field := range layout.Fields {
+			fmt.Fprintf(c.stdout, "%-12v %-10v bits %2d-%-2d  %0*b\n",
+				field.Name+":", field.Value(raw), field.Shift+field.Width-1, field.Shift, field.Width, field.Bits(raw))
+		}
+		if validateErr != nil {
+			fmt.Fprintf(c.stdout, "invalid:     %v\n", validateErr)
+		}
+		return nil
+	})
+}
+
+func formatSmalltime(value smalltime.Smalltime) (string, error) {
+	return smalltime.Format(value), value.Validate()
+}
+
+func formatNanotime(value smalltime.Nanotime) (string, error) {
+	return smalltime.Format(value), value.Validate()
+}
+
+// ============================================================================
+
+func runEncode(c *context, args []string) int {
+	flags := c.newFlagSet("encode", "[-nano] [iso8601 | year month day hour minute second [subsecond]]")
+	nano := flags.Bool("nano", false, "Encode as nanotime instead of smalltime")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	encode := func(str string) error {
+		var raw uint64
+		if fields := strings.Fields(str); len(fields) > 1 {
+			values, err := parseFields(fields)
+			if err != nil {
+				return err
+			}
+			if raw, err = encodeFields(values, *nano); err != nil {
+				return err
+			}
+		} else if *nano {
+			value, err := smalltime.ParseNanotime(str)
+			if err != nil {
+				return err
+			}
+			raw = uint64(value)
+		} else {
+			value, err := smalltime.ParseSmalltime(str)
+			if err != nil {
+				return err
+			}
+			raw = uint64(value)
+		}
+		fmt.Fprintln(c.stdout, formatRaw(raw))
+		return nil
+	}
+
+	if flags.NArg() > 1 {
+		return c.forEachValue([]string{strings.Join(flags.Args(), " ")}, encode)
+	}
+	return c.forEachValue(flags.Args(), encode)
+}
+
+func parseFields(fields []string) ([]int, error) {
+	if len(fields) < 6 || len(fields) > 7 {
+		return nil, fmt.Errorf("expected 6 or 7 fields (year month day hour minute second [subsecond]) but got %v", len(fields))
+	}
+	values := make([]int, 7)
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid field value", field)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func encodeFields(v []int, nano bool) (uint64, error) {
+	if nano {
+		if minYear, maxYear := smalltime.MinNanotime.Year(), smalltime.MaxNanotime.Year(); v[0] < minYear || v[0] > maxYear {
+			return 0, &smalltime.RangeError{Field: "year", Value: v[0], Min: minYear, Max: maxYear}
+		}
+		value := smalltime.NewNanotime(v[0], v[1], v[2], v[3], v[4], v[5], v[6])
+		return uint64(value), value.Validate()
+	}
+	if minYear, maxYear := smalltime.MinSmalltime.Year(), smalltime.MaxSmalltime.Year(); v[0] < minYear || v[0] > maxYear {
+		return 0, &smalltime.RangeError{Field: "year", Value: v[0], Min: minYear, Max: maxYear}
+	}
+	value := smalltime.NewSmalltime(v[0], v[1], v[2], v[3], v[4], v[5], v[6])
+	return uint64(value), value.Validate()
+}
+
+// ============================================================================
+
+func runNow(c *context, args []string) int {
+	flags := c.newFlagSet("now", "[-nano]")
+	nano := flags.Bool("nano", false, "Print the time as nanotime instead of smalltime")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	now := c.now()
+	if *nano {
+		value := smalltime.NanotimeFromTime(now)
+		fmt.Fprintf(c.stdout, "%v %v\n", formatRaw(uint64(value)), smalltime.Format(value))
+	} else {
+		value := smalltime.SmalltimeFromTime(now)
+		fmt.Fprintf(c.stdout, "%v %v\n", formatRaw(uint64(value)), smalltime.Format(value))
+	}
+	return exitOK
+}
+
+// ============================================================================
+
+var formats = []string{"smalltime", "nanotime", "iso8601", "unix", "unixmilli", "unixmicro", "unixnano"}
+
+// The common representation that all formats convert through. Unlike
+// time.Time, it can hold a leap second.
+type civil struct {
+	year, month, day, hour, minute, second, nanos int
+}
+
+func civilFromTime(t time.Time) civil {
+	t = t.UTC()
+	return civil{t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond()}
+}
+
+func (v civil) asTime() time.Time {
+	return time.Date(v.year, time.Month(v.month), v.day, v.hour, v.minute, v.second, v.nanos, time.UTC)
+}
+
+func civilFromSmalltime(t smalltime.Smalltime) civil {
+	return civil{t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.SubsecondNanos()}
+}
+
+func civilFromNanotime(t smalltime.Nanotime) civil {
+	return civil{t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.SubsecondNanos()}
+}
+
+func parseCivil(format, str string) (civil, error) {
+	switch format {
+	case "smalltime", "nanotime":
+		raw, err := parseRaw(str)
+		if err != nil {
+			return civil{}, err
+		}
+		if format == "nanotime" {
+			value := smalltime.Nanotime(raw)
+			return civilFromNanotime(value), value.Validate()
+		}
+		value := smalltime.Smalltime(raw)
+		return civilFromSmalltime(value), value.Validate()
+	case "iso8601":
+		// Smalltime has the widest range, but truncates to microseconds.
+		value, err := smalltime.ParseSmalltime(str)
+		if err != nil {
+			return civil{}, err
+		}
+		if value.IsInfinite() {
+			return civil{}, fmt.Errorf("%v cannot be converted", str)
+		}
+		if nanoValue, err := smalltime.ParseNanotime(str); err == nil {
+			return civilFromNanotime(nanoValue), nil
+		}
+		return civilFromSmalltime(value), nil
+	}
+
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return civil{}, fmt.Errorf("%q is not a valid %v value", str, format)
+	}
+	switch format {
+	case "unix":
+		return civilFromTime(time.Unix(value, 0)), nil
+	case "unixmilli":
+		return civilFromTime(time.UnixMilli(value)), nil
+	case "unixmicro":
+		return civilFromTime(time.UnixMicro(value)), nil
+	default:
+		return civilFromTime(time.Unix(0, value)), nil
+	}
+}
+
+func formatCivil(format string, v civil) (string, error) {
+	switch format {
+	case "smalltime":
+		raw, err := encodeFields([]int{v.year, v.month, v.day, v.hour, v.minute, v.second, v.nanos / 1000}, false)
+		return formatRaw(raw), err
+	case "nanotime":
+		raw, err := encodeFields([]int{v.year, v.month, v.day, v.hour, v.minute, v.second, v.nanos}, true)
+		return formatRaw(raw), err
+	case "iso8601":
+		raw, err := encodeFields([]int{v.year, v.month, v.day, v.hour, v.minute, v.second, v.nanos / 1000}, false)
+		if err != nil {
+			return "", err
+		}
+		if raw, err := encodeFields([]int{v.year, v.month, v.day, v.hour, v.minute, v.second, v.nanos}, true); err == nil {
+			return smalltime.Format(smalltime.Nanotime(raw)), nil
+		}
+		return smalltime.Format(smalltime.Smalltime(raw)), nil
+	case "unix":
+		return strconv.FormatInt(v.asTime().Unix(), 10), nil
+	case "unixmilli":
+		return strconv.FormatInt(v.asTime().UnixMilli(), 10), nil
+	case "unixmicro":
+		return strconv.FormatInt(v.asTime().UnixMicro(), 10), nil
+	default:
+		return strconv.FormatInt(v.asTime().UnixNano(), 10), nil
+	}
+}
+
+func isFormat(name string) bool {
+	for _, format := range formats {
+		if format == name {
+			return true
+		}
+	}
+	return false
+}
+
+func runConvert(c *context, args []string) int {
+	flags := c.newFlagSet("convert", "-from format -to format [value ...]")
+	formatList := strings.Join(formats, ", ")
+	from := flags.String("from", "smalltime", "Format of the input values ("+formatList+")")
+	to := flags.String("to", "iso8601", "Format to convert to ("+formatList+")")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	for _, format := range []string{*from, *to} {
+		if !isFormat(format) {
+			fmt.Fprintf(c.stderr, "smalltime: unknown format %q (expected one of %v)\n", format, formatList)
+			return exitUsage
+		}
+	}
+
+	return c.forEachValue(flags.Args(), func(str string) error {
+		value, err := parseCivil(*from, str)
+		if err != nil {
+			return err
+		}
+		result, err := formatCivil(*to, value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, result)
+		return nil
+	})
+}
+
+// ============================================================================
+
+func runValidate(c *context, args []string) int {
+	flags := c.newFlagSet("validate", "[-nano] [value ...]")
+	nano := flags.Bool("nano", false, "Validate values as nanotime instead of smalltime")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	return c.forEachValue(flags.Args(), func(str string) error {
+		raw, err := parseRaw(str)
+		if err != nil {
+			return err
+		}
+		if *nano {
+			err = smalltime.Nanotime(raw).Validate()
+		} else {
+			err = smalltime.Smalltime(raw).Validate()
+		}
+		if err != nil {
+			fmt.Fprintf(c.stdout, "%v: %v\n", str, err)
+			return fmt.Errorf("%v is invalid", str)
+		}
+		fmt.Fprintf(c.stdout, "%v: ok\n", str)
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runWith(t *testing.T, stdin string, args ...string) (stdout string, exitCode int) {
+	var out, errOut bytes.Buffer
+	c := &context{
+		stdin:  strings.NewReader(stdin),
+		stdout: &out,
+		stderr: &errOut,
+		now:    func() time.Time { return time.Date(1999, 2, 15, 12, 8, 45, 9122000, time.UTC) },
+	}
+	exitCode = c.run(args)
+	return out.String(), exitCode
+}
+
+func assertRun(t *testing.T, stdin string, args []string, expectedOut string, expectedCode int) {
+	out, code := runWith(t, stdin, args...)
+	if code != expectedCode {
+		t.Errorf("%v: expected exit code %v but got %v", args, expectedCode, code)
+	}
+	if out != expectedOut {
+		t.Errorf("%v: expected output\n%v\nbut got\n%v", args, expectedOut, out)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	assertRun(t, "", []string{"encode", "1999-02-15T12:08:45.009122Z"}, "0x01f3c9ec22d023a2\n", exitOK)
+	assertRun(t, "", []string{"encode", "1999", "2", "15", "12", "8", "45", "9122"}, "0x01f3c9ec22d023a2\n", exitOK)
+	assertRun(t, "", []string{"encode", "-nano", "1970-01-01T00:00:00.000000001Z"}, "0x0010800000000001\n", exitOK)
+	assertRun(t, "", []string{"encode", "1999-02-30"}, "", exitInvalid)
+	assertRun(t, "", []string{"encode", "1999", "2", "30", "0", "0", "0"}, "", exitInvalid)
+}
+
+func TestDecode(t *testing.T) {
+	expected := `raw:         0x01f3c9ec22d023a2
+iso8601:     1999-02-15T12:08:45.009122Z
+year:        1999       bits 63-46  000000011111001111
+month:       2          bits 45-42  0010
+day:         15         bits 41-37  01111
+hour:        12         bits 36-32  01100
+minute:      8          bits 31-26  001000
+second:      45         bits 25-20  101101
+microsecond: 9122       bits 19-0   00000010001110100010
+`
+	assertRun(t, "", []string{"decode", "0x01f3c9ec22d023a2"}, expected, exitOK)
+	assertRun(t, "", []string{"decode", "140678029412148130"}, expected, exitOK)
+
+	out, code := runWith(t, "", "decode", "-nano", "0xffffffffffffffff")
+	if code != exitOK || !strings.Contains(out, "year:        2225") || !strings.Contains(out, "invalid:") {
+		t.Errorf("Unexpected nanotime decode output (%v):\n%v", code, out)
+	}
+	assertRun(t, "", []string{"decode", "xyz"}, "", exitInvalid)
+}
+
+func TestDecodeNegativeYear(t *testing.T) {
+	out, code := runWith(t, "", "decode", "--", "-1")
+	if code != exitOK || !strings.Contains(out, "year:        -1 ") {
+		t.Errorf("Unexpected output (%v):\n%v", code, out)
+	}
+}
+
+func TestNow(t *testing.T) {
+	assertRun(t, "", []string{"now"}, "0x01f3c9ec22d023a2 1999-02-15T12:08:45.009122Z\n", exitOK)
+	assertRun(t, "", []string{"now", "-nano"}, "0x1d27b08b408b30d0 1999-02-15T12:08:45.009122Z\n", exitOK)
+}
+
+func TestConvert(t *testing.T) {
+	assertRun(t, "", []string{"convert", "-from", "iso8601", "-to", "unix", "1999-02-15T12:08:45Z"}, "919080525\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "unix", "-to", "iso8601", "919080525"}, "1999-02-15T12:08:45Z\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "unixmicro", "-to", "smalltime", "919080525009122"}, "0x01f3c9ec22d023a2\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "smalltime", "-to", "unixmilli", "0x01f3c9ec22d023a2"}, "919080525009\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "nanotime", "-to", "unixnano", "0x1d27b08b408b30d0"}, "919080525009122000\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "smalltime", "-to", "nanotime", "0x01f3c9ec22d023a2"}, "0x1d27b08b408b30d0\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "iso8601", "-to", "iso8601", "2016-12-31T23:59:60.123456789Z"}, "2016-12-31T23:59:60.123456789Z\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "iso8601", "-to", "iso8601", "--", "-0044-03-15T00:00:00Z"}, "-0044-03-15T00:00:00Z\n", exitOK)
+	assertRun(t, "", []string{"convert", "-from", "iso8601", "-to", "nanotime", "1969-12-31T23:59:59Z"}, "", exitInvalid)
+	assertRun(t, "", []string{"convert", "-from", "bogus", "1"}, "", exitUsage)
+}
+
+func TestValidate(t *testing.T) {
+	assertRun(t, "", []string{"validate", "0x01f3c9ec22d023a2"}, "0x01f3c9ec22d023a2: ok\n", exitOK)
+	assertRun(t, "", []string{"validate", "0x01f3c9fc22d023a2"},
+		"0x01f3c9fc22d023a2: smalltime: hour 28 is out of range [0, 23]\n", exitInvalid)
+}
+
+func TestBatch(t *testing.T) {
+	stdin := "919080525\n\n  0\n"
+	assertRun(t, stdin, []string{"convert", "-from", "unix"}, "1999-02-15T12:08:45Z\n1970-01-01T00:00:00Z\n", exitOK)
+
+	stdin = "1999-02-15T12:08:45.009122Z\nnot a date\n1970-01-01\n"
+	assertRun(t, stdin, []string{"encode"}, "0x01f3c9ec22d023a2\n0x01ec842000000000\n", exitInvalid)
+}
+
+func TestUsage(t *testing.T) {
+	assertRun(t, "", nil, "", exitUsage)
+	assertRun(t, "", []string{"bogus"}, "", exitUsage)
+	assertRun(t, "", []string{"now", "extra"}, "", exitUsage)
+}
@@ -4,11 +4,18 @@ Smalltime and Nanotime are binary date & time representations that fit into
 standard Gregorian fields are easily extractable.
 
 Smalltime is based off a signed 64-bit integer. It has a range of hundreds of
-thousands of years, but only goes down to the microseconds.
+thousands of years, but only goes down to the microseconds. Years before the
+common era use astronomical numbering on the proleptic Gregorian calendar
+(year 0 is 1 BC, year -1 is 2 BC).
 
 Nanotime is based off an unsigned 64-bit integer. It goes down to the
 nanosecond, but only has a range of 256 years (1970 - 2226).
 
+Smalltime32 and Smalltime48 are compact variants for size constrained
+payloads. Smalltime32 goes down to the second and covers 64 years from a
+caller-chosen epoch year. Smalltime48 goes down to the millisecond and covers
+the years 0 - 4095.
+
 
 Specifications:
 
@@ -30,6 +37,58 @@ Features
 */
 package smalltime
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOutOfRange is matched (using errors.Is) by every RangeError.
+var ErrOutOfRange = errors.New("smalltime: value out of range")
+
+// RangeError reports a field value that is outside of its permitted range.
+type RangeError struct {
+	Field string
+	Value int
+	Min   int
+	Max   int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("smalltime: %v %v is out of range [%v, %v]", e.Field, e.Value, e.Min, e.Max)
+}
+
+func (e *RangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+func checkRange(field string, value, min, max int) error {
+	if value < min || value > max {
+		return &RangeError{Field: field, Value: value, Min: min, Max: max}
+	}
+	return nil
+}
+
+// Returns the first out of range field of a date & time (the year is not
+// checked), or nil if all are in range.
+func validateFields(year, month, day, hour, minute, second, subsecond, maxSubsecond int, subsecondName string) error {
+	if err := checkRange("month", month, 1, 12); err != nil {
+		return err
+	}
+	checks := []error{
+		checkRange("day", day, 1, daysInMonth(year, month)),
+		checkRange("hour", hour, 0, 23),
+		checkRange("minute", minute, 0, 59),
+		checkRange("second", second, 0, 60),
+		checkRange(subsecondName, subsecond, 0, maxSubsecond),
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func isLeapYear(year int) bool {
 	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
 }
@@ -57,3 +116,103 @@ func doyToYmd(year, doy int) (month, day int) {
 	month = (monthsFromMarch+2)%12 + 1        // [1, 12]
 	return month, day
 }
+
+// Truncate a nanosecond value to the specified number of decimal places,
+// clamped to [0, 9].
+func fraction(nanos, digits int) int {
+	if digits <= 0 {
+		return 0
+	}
+	for ; digits < 9; digits++ {
+		nanos /= 10
+	}
+	return nanos
+}
+
+var daysInMonthTable = [...]int{0, 31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+func daysInMonth(year, month int) int {
+	if month == 2 && isLeapYear(year) {
+		return 29
+	}
+	return daysInMonthTable[month]
+}
+
+// DayPolicy controls what WithYear and WithMonth do when the existing day of
+// month doesn't exist in the new month (for example Jan 31 changed to Feb).
+type DayPolicy int
+
+const (
+	// Leave the day unchanged, even if the resulting date is invalid.
+	KeepDay DayPolicy = iota
+	// Return a RangeError if the day doesn't exist in the new month.
+	CheckDay
+	// Clamp the day to the last day of the new month.
+	ClampDay
+)
+
+// CheckDay and ClampDay need a valid month (the stored month field can hold
+// 13-15) to look up its length, so they return a RangeError otherwise.
+func applyDayPolicy(policy DayPolicy, year, month, day int) (int, error) {
+	if policy == CheckDay || policy == ClampDay {
+		if err := checkRange("month", month, 1, 12); err != nil {
+			return day, err
+		}
+	}
+	switch policy {
+	case CheckDay:
+		return day, checkRange("day", day, 1, daysInMonth(year, month))
+	case ClampDay:
+		if max := daysInMonth(year, month); day > max {
+			return max, nil
+		}
+	}
+	return day, nil
+}
+
+// Days since 1970-01-01 in the proleptic Gregorian calendar.
+// Algorithm from http://howardhinnant.github.io/date_algorithms.html
+func daysFromCivil(year, month, day int) int64 {
+	y := int64(year)
+	if month <= 2 {
+		y--
+	}
+	era := y / 400
+	if y < 0 && y%400 != 0 {
+		era--
+	}
+	yearOfEra := y - era*400                                            // [0, 399]
+	dayOfYear := int64((153*((month+9)%12)+2)/5 + day - 1)              // [0, 365]
+	dayOfEra := yearOfEra*365 + yearOfEra/4 - yearOfEra/100 + dayOfYear // [0, 146096]
+	return era*146097 + dayOfEra - 719468
+}
+
+func civilFromDays(days int64) (year, month, day int) {
+	days += 719468
+	era := days / 146097
+	if days < 0 && days%146097 != 0 {
+		era--
+	}
+	dayOfEra := days - era*146097                                                    // [0, 146096]
+	yearOfEra := (dayOfEra - dayOfEra/1460 + dayOfEra/36524 - dayOfEra/146096) / 365 // [0, 399]
+	dayOfYear := dayOfEra - (365*yearOfEra + yearOfEra/4 - yearOfEra/100)            // [0, 365]
+	monthsFromMarch := (5*dayOfYear + 2) / 153                                       // [0, 11]
+	day = int(dayOfYear - (153*monthsFromMarch+2)/5 + 1)                             // [1, 31]
+	month = int((monthsFromMarch+2)%12 + 1)                                          // [1, 12]
+	year = int(yearOfEra + era*400)
+	if month <= 2 {
+		year++
+	}
+	return year, month, day
+}
+
+// Floored division and modulus, for splitting negative Unix times.
+func floorDivMod(value, divisor int64) (quotient, remainder int64) {
+	quotient = value / divisor
+	remainder = value % divisor
+	if remainder < 0 {
+		quotient--
+		remainder += divisor
+	}
+	return quotient, remainder
+}
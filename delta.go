@@ -0,0 +1,231 @@
+package smalltime
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// The delta codec compresses a series of Smalltimes using Gorilla-style
+// delta-of-delta encoding of the raw 64-bit values. Regularly spaced values
+// produce a delta-of-delta of 0 (a single bit) except where a field rolls over
+// into the next one, so sorted series typically cost only a few bits per value.
+//
+// Each value is written as a variable length bit sequence:
+//
+//     0                        delta-of-delta is 0
+//     10    + 14 bits          delta-of-delta in [-2^13, 2^13)
+//     110   + 24 bits          delta-of-delta in [-2^23, 2^23)
+//     1110  + 32 bits          delta-of-delta in [-2^31, 2^31)
+//     11110 + 64 bits          any other delta-of-delta
+//     11111                    end of stream
+//
+// The stream is padded with zero bits to a byte boundary after the end marker.
+
+type deltaBucket struct {
+	prefix     uint64
+	prefixBits uint
+	valueBits  uint
+}
+
+var deltaBuckets = [...]deltaBucket{
+	{prefix: 0x2, prefixBits: 2, valueBits: 14},
+	{prefix: 0x6, prefixBits: 3, valueBits: 24},
+	{prefix: 0xe, prefixBits: 4, valueBits: 32},
+	{prefix: 0x1e, prefixBits: 5, valueBits: 64},
+}
+
+const deltaEndMarker = 0x1f
+const deltaEndMarkerBits = 5
+
+const deltaFlushThreshold = 4096
+
+// DeltaEncoder compresses a stream of Smalltimes to an io.Writer. Close must be
+// called to terminate the stream.
+type DeltaEncoder struct {
+	writer    io.Writer
+	buffer    []byte
+	bits      uint64
+	bitCount  uint
+	previous  Smalltime
+	lastDelta Smalltime
+	err       error
+}
+
+func NewDeltaEncoder(writer io.Writer) *DeltaEncoder {
+	return &DeltaEncoder{writer: writer}
+}
+
+func (e *DeltaEncoder) writeBits(value uint64, count uint) {
+	for count > 0 {
+		chunk := count
+		if free := 64 - e.bitCount; chunk > free {
+			chunk = free
+		}
+		count -= chunk
+		e.bits = e.bits<<chunk | (value>>count)&(1<<chunk-1)
+		e.bitCount += chunk
+		for e.bitCount >= 8 {
+			e.bitCount -= 8
+			e.buffer = append(e.buffer, byte(e.bits>>e.bitCount))
+		}
+	}
+}
+
+func (e *DeltaEncoder) flush() error {
+	if e.err == nil && len(e.buffer) > 0 {
+		_, e.err = e.writer.Write(e.buffer)
+		e.buffer = e.buffer[:0]
+	}
+	return e.err
+}
+
+func (e *DeltaEncoder) Append(t Smalltime) error {
+	if e.err != nil {
+		return e.err
+	}
+	delta := t - e.previous
+	deltaOfDelta := int64(delta - e.lastDelta)
+	e.previous = t
+	e.lastDelta = delta
+
+	if deltaOfDelta == 0 {
+		e.writeBits(0, 1)
+	} else {
+		for _, bucket := range deltaBuckets {
+			limit := int64(1) << (bucket.valueBits - 1)
+			if bucket.valueBits == 64 || (deltaOfDelta >= -limit && deltaOfDelta < limit) {
+				e.writeBits(bucket.prefix, bucket.prefixBits)
+				e.writeBits(uint64(deltaOfDelta), bucket.valueBits)
+				break
+			}
+		}
+	}
+
+	if len(e.buffer) >= deltaFlushThreshold {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close writes the end of stream marker and flushes any buffered data. It does
+// not close the underlying writer.
+func (e *DeltaEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.writeBits(deltaEndMarker, deltaEndMarkerBits)
+	if e.bitCount > 0 {
+		e.writeBits(0, 8-e.bitCount)
+	}
+	return e.flush()
+}
+
+// DeltaDecoder decompresses a stream of Smalltimes written by a DeltaEncoder.
+type DeltaDecoder struct {
+	reader    io.ByteReader
+	bits      uint64
+	bitCount  uint
+	previous  Smalltime
+	lastDelta Smalltime
+	err       error
+}
+
+func NewDeltaDecoder(reader io.Reader) *DeltaDecoder {
+	byteReader, ok := reader.(io.ByteReader)
+	if !ok {
+		byteReader = bufio.NewReader(reader)
+	}
+	return &DeltaDecoder{reader: byteReader}
+}
+
+func (d *DeltaDecoder) readBits(count uint) uint64 {
+	var value uint64
+	for count > 0 {
+		if d.bitCount == 0 {
+			b, err := d.reader.ReadByte()
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				d.err = err
+				return 0
+			}
+			d.bits = uint64(b)
+			d.bitCount = 8
+		}
+		chunk := count
+		if chunk > d.bitCount {
+			chunk = d.bitCount
+		}
+		d.bitCount -= chunk
+		count -= chunk
+		value = value<<chunk | (d.bits>>d.bitCount)&(1<<chunk-1)
+	}
+	return value
+}
+
+func signExtend(value uint64, bits uint) int64 {
+	shift := 64 - bits
+	return int64(value<<shift) >> shift
+}
+
+// Next returns the next value in the stream, or io.EOF once the end of stream
+// marker has been read.
+func (d *DeltaDecoder) Next() (Smalltime, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	var deltaOfDelta int64
+	prefixBits := uint(0)
+	for prefixBits < deltaEndMarkerBits && d.readBits(1) == 1 {
+		prefixBits++
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	switch prefixBits {
+	case 0:
+	case deltaEndMarkerBits:
+		d.err = io.EOF
+		return 0, d.err
+	default:
+		bucket := deltaBuckets[prefixBits-1]
+		deltaOfDelta = signExtend(d.readBits(bucket.valueBits), bucket.valueBits)
+		if d.err != nil {
+			return 0, d.err
+		}
+	}
+
+	d.lastDelta += Smalltime(deltaOfDelta)
+	d.previous += d.lastDelta
+	return d.previous, nil
+}
+
+// EncodeDeltas compresses a series of Smalltimes into a byte slice.
+func EncodeDeltas(values []Smalltime) []byte {
+	var buffer bytes.Buffer
+	encoder := NewDeltaEncoder(&buffer)
+	for _, v := range values {
+		encoder.Append(v)
+	}
+	encoder.Close()
+	return buffer.Bytes()
+}
+
+// DecodeDeltas decompresses a byte slice produced by EncodeDeltas.
+func DecodeDeltas(data []byte) ([]Smalltime, error) {
+	decoder := NewDeltaDecoder(bytes.NewReader(data))
+	var values []Smalltime
+	for {
+		v, err := decoder.Next()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return values, err
+		}
+		values = append(values, v)
+	}
+}
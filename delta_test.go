@@ -0,0 +1,134 @@
+package smalltime
+
+import "bytes"
+import "io"
+import "math/rand"
+import "testing"
+import "time"
+
+func assertDeltaRoundTrip(t *testing.T, values []Smalltime) []byte {
+	encoded := EncodeDeltas(values)
+	decoded, err := DecodeDeltas(encoded)
+	if err != nil {
+		t.Errorf("Unexpected error decoding %v values: %v", len(values), err)
+		return encoded
+	}
+	if len(decoded) != len(values) {
+		t.Errorf("Expected %v values but got %v", len(values), len(decoded))
+		return encoded
+	}
+	for i := range values {
+		if decoded[i] != values[i] {
+			t.Errorf("Value %v: Expected %016x but got %016x", i, values[i], decoded[i])
+			break
+		}
+	}
+	return encoded
+}
+
+func regularSeries(start time.Time, interval time.Duration, count int) []Smalltime {
+	values := make([]Smalltime, count)
+	for i := range values {
+		values[i] = SmalltimeFromTime(start.Add(interval * time.Duration(i)))
+	}
+	return values
+}
+
+func jitterySeries(random *rand.Rand, start time.Time, interval, jitter time.Duration, count int) []Smalltime {
+	values := make([]Smalltime, count)
+	for i := range values {
+		offset := time.Duration(random.Int63n(int64(jitter)))
+		values[i] = SmalltimeFromTime(start.Add(interval*time.Duration(i) + offset))
+	}
+	return values
+}
+
+var deltaTestStart = time.Date(2019, 5, 20, 23, 58, 30, 0, time.UTC)
+
+func TestDeltaRoundTrip(t *testing.T) {
+	assertDeltaRoundTrip(t, nil)
+	assertDeltaRoundTrip(t, []Smalltime{0})
+	assertDeltaRoundTrip(t, []Smalltime{NewSmalltime(-131072, 1, 1, 0, 0, 0, 0), NewSmalltime(131071, 12, 31, 23, 59, 60, 999999), 0})
+	assertDeltaRoundTrip(t, regularSeries(deltaTestStart, time.Second, 10000))
+	assertDeltaRoundTrip(t, regularSeries(deltaTestStart, time.Millisecond*250, 10000))
+	assertDeltaRoundTrip(t, jitterySeries(rand.New(rand.NewSource(1)), deltaTestStart, time.Second, time.Millisecond*10, 10000))
+
+	random := rand.New(rand.NewSource(1))
+	values := make([]Smalltime, 1000)
+	for i := range values {
+		values[i] = Smalltime(random.Uint64())
+	}
+	assertDeltaRoundTrip(t, values)
+}
+
+func TestDeltaCompression(t *testing.T) {
+	values := regularSeries(deltaTestStart, time.Second, 3600)
+	encoded := assertDeltaRoundTrip(t, values)
+	if bits := len(encoded) * 8 / len(values); bits > 4 {
+		t.Errorf("Expected a regular series to compress to at most 4 bits per value but got %v", bits)
+	}
+}
+
+func TestDeltaTruncated(t *testing.T) {
+	encoded := EncodeDeltas(regularSeries(deltaTestStart, time.Second, 100))
+	decoder := NewDeltaDecoder(bytes.NewReader(encoded[:len(encoded)-2]))
+	var err error
+	for err == nil {
+		_, err = decoder.Next()
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF but got %v", err)
+	}
+}
+
+func TestDeltaStreaming(t *testing.T) {
+	values := jitterySeries(rand.New(rand.NewSource(2)), deltaTestStart, time.Minute, time.Second, 20000)
+	reader, writer := io.Pipe()
+	go func() {
+		encoder := NewDeltaEncoder(writer)
+		for _, v := range values {
+			encoder.Append(v)
+		}
+		writer.CloseWithError(encoder.Close())
+	}()
+
+	decoder := NewDeltaDecoder(reader)
+	for i, expected := range values {
+		actual, err := decoder.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error at value %v: %v", i, err)
+		}
+		if actual != expected {
+			t.Fatalf("Value %v: Expected %016x but got %016x", i, expected, actual)
+		}
+	}
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF but got %v", err)
+	}
+}
+
+func benchmarkDeltaEncode(b *testing.B, values []Smalltime) {
+	var encoded []byte
+	for i := 0; i < b.N; i++ {
+		encoded = EncodeDeltas(values)
+	}
+	b.ReportMetric(float64(len(encoded)*8)/float64(len(values)), "bits/timestamp")
+}
+
+func BenchmarkDeltaEncodeRegular(b *testing.B) {
+	benchmarkDeltaEncode(b, regularSeries(deltaTestStart, time.Second, 100000))
+}
+
+func BenchmarkDeltaEncodeJittery(b *testing.B) {
+	benchmarkDeltaEncode(b, jitterySeries(rand.New(rand.NewSource(1)), deltaTestStart, time.Second, time.Millisecond*10, 100000))
+}
+
+func BenchmarkDeltaDecodeRegular(b *testing.B) {
+	values := regularSeries(deltaTestStart, time.Second, 100000)
+	encoded := EncodeDeltas(values)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeDeltas(encoded)
+	}
+	b.ReportMetric(float64(len(encoded)*8)/float64(len(values)), "bits/timestamp")
+}
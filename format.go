@@ -0,0 +1,710 @@
+package smalltime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type elementKind int
+
+const (
+	elementLiteral elementKind = iota
+	elementYear
+	elementYearTwoDigit
+	elementMonth
+	elementMonthName
+	elementDay
+	elementDayOfYear
+	elementWeekdayName
+	elementWeekdayNumber
+	elementHour
+	elementHour12
+	elementMinute
+	elementSecond
+	elementFraction
+	elementAMPM
+	elementZoneOffset
+	elementZoneName
+)
+
+// A compiled piece of a layout, shared by the Go reference layout and
+// strftime syntaxes.
+type layoutElement struct {
+	kind elementKind
+	// Literal text, or the separator before a Go-style fraction
+	text string
+	// Minimum digits for numbers, or digits for fractions
+	width int
+	// '0', ' ', or 0 for no padding
+	pad byte
+	// Fraction: drop trailing zeroes (Go's .999), and allow it to be absent when parsing
+	trim bool
+	// Month and weekday names: the form of the name
+	names NameWidth
+	// AM/PM: lower case. Weekday number: 1-7 from Monday rather than 0-6 from Sunday
+	alternate bool
+	// Zone offset: render zero as Z, separate hours and minutes with ':', omit minutes
+	zulu, colon, hoursOnly bool
+}
+
+func (e layoutElement) isNumeric() bool {
+	switch e.kind {
+	case elementLiteral, elementMonthName, elementWeekdayName, elementAMPM, elementZoneOffset, elementZoneName:
+		return false
+	case elementFraction:
+		return e.text == ""
+	}
+	return true
+}
+
+// Compiled layouts, keyed by the layout string. The number of entries is
+// capped so that formatting with many one-off layouts can't grow it forever.
+var compiledLayouts sync.Map
+var compiledLayoutCount int64
+
+const maxCompiledLayouts = 1000
+
+// compileLayout compiles a strftime layout if it contains '%', or a Go
+// reference layout otherwise. The result is cached, and must not be modified.
+func compileLayout(layout string) []layoutElement {
+	if elements, ok := compiledLayouts.Load(layout); ok {
+		return elements.([]layoutElement)
+	}
+	var elements []layoutElement
+	if strings.IndexByte(layout, '%') >= 0 {
+		elements = compileStrftime(layout)
+	} else {
+		elements = compileGoLayout(layout)
+	}
+	if atomic.LoadInt64(&compiledLayoutCount) < maxCompiledLayouts {
+		if _, loaded := compiledLayouts.LoadOrStore(layout, elements); !loaded {
+			atomic.AddInt64(&compiledLayoutCount, 1)
+		}
+	}
+	return elements
+}
+
+func appendLiteral(elements []layoutElement, text string) []layoutElement {
+	if text == "" {
+		return elements
+	}
+	if last := len(elements) - 1; last >= 0 && elements[last].kind == elementLiteral {
+		elements[last].text += text
+		return elements
+	}
+	return append(elements, layoutElement{kind: elementLiteral, text: text})
+}
+
+// Go reference layout chunks, longest first where one is a prefix of another.
+var goLayoutChunks = []struct {
+	chunk   string
+	element layoutElement
+}{
+	{"January", layoutElement{kind: elementMonthName, names: NameWide}},
+	{"Jan", layoutElement{kind: elementMonthName}},
+	{"Monday", layoutElement{kind: elementWeekdayName, names: NameWide}},
+	{"Mon", layoutElement{kind: elementWeekdayName}},
+	{"MST", layoutElement{kind: elementZoneName}},
+	{"2006", layoutElement{kind: elementYear, width: 4, pad: '0'}},
+	{"002", layoutElement{kind: elementDayOfYear, width: 3, pad: '0'}},
+	{"__2", layoutElement{kind: elementDayOfYear, width: 3, pad: ' '}},
+	{"01", layoutElement{kind: elementMonth, width: 2, pad: '0'}},
+	{"02", layoutElement{kind: elementDay, width: 2, pad: '0'}},
+	{"_2", layoutElement{kind: elementDay, width: 2, pad: ' '}},
+	{"03", layoutElement{kind: elementHour12, width: 2, pad: '0'}},
+	{"04", layoutElement{kind: elementMinute, width: 2, pad: '0'}},
+	{"05", layoutElement{kind: elementSecond, width: 2, pad: '0'}},
+	{"06", layoutElement{kind: elementYearTwoDigit, width: 2, pad: '0'}},
+	{"15", layoutElement{kind: elementHour, width: 2, pad: '0'}},
+	{"1", layoutElement{kind: elementMonth, width: 2}},
+	{"2", layoutElement{kind: elementDay, width: 2}},
+	{"3", layoutElement{kind: elementHour12, width: 2}},
+	{"4", layoutElement{kind: elementMinute, width: 2}},
+	{"5", layoutElement{kind: elementSecond, width: 2}},
+	{"PM", layoutElement{kind: elementAMPM}},
+	{"pm", layoutElement{kind: elementAMPM, alternate: true}},
+	{"Z07:00", layoutElement{kind: elementZoneOffset, zulu: true, colon: true}},
+	{"Z0700", layoutElement{kind: elementZoneOffset, zulu: true}},
+	{"Z07", layoutElement{kind: elementZoneOffset, zulu: true, hoursOnly: true}},
+	{"-07:00", layoutElement{kind: elementZoneOffset, colon: true}},
+	{"-0700", layoutElement{kind: elementZoneOffset}},
+	{"-07", layoutElement{kind: elementZoneOffset, hoursOnly: true}},
+}
+
+func compileGoLayout(layout string) (elements []layoutElement) {
+	literalStart := 0
+	for i := 0; i < len(layout); {
+		element, length := matchGoChunk(layout[i:])
+		if length == 0 {
+			i++
+			continue
+		}
+		elements = appendLiteral(elements, layout[literalStart:i])
+		elements = append(elements, element)
+		i += length
+		literalStart = i
+	}
+	return appendLiteral(elements, layout[literalStart:])
+}
+
+func matchGoChunk(layout string) (layoutElement, int) {
+	// .000 or ,999 etc, as long as no digit follows
+	if len(layout) > 1 && (layout[0] == '.' || layout[0] == ',') && (layout[1] == '0' || layout[1] == '9') {
+		end := 2
+		for end < len(layout) && layout[end] == layout[1] {
+			end++
+		}
+		if end >= len(layout) || layout[end] < '0' || layout[end] > '9' {
+			return layoutElement{kind: elementFraction, text: layout[:1], width: end - 1, trim: layout[1] == '9'}, end
+		}
+	}
+	// _2006 is a literal underscore followed by the year
+	if strings.HasPrefix(layout, "_2006") {
+		return layoutElement{}, 0
+	}
+	for _, c := range goLayoutChunks {
+		if strings.HasPrefix(layout, c.chunk) {
+			return c.element, len(c.chunk)
+		}
+	}
+	return layoutElement{}, 0
+}
+
+// strftime conversions that expand to other conversions
+var strftimeComposites = map[byte]string{
+	'F': "%Y-%m-%d",
+	'T': "%H:%M:%S",
+	'D': "%m/%d/%y",
+	'R': "%H:%M",
+	'c': "%a %b %e %H:%M:%S %Y",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+var strftimeConversions = map[byte]layoutElement{
+	'Y': {kind: elementYear, width: 4, pad: '0'},
+	'y': {kind: elementYearTwoDigit, width: 2, pad: '0'},
+	'm': {kind: elementMonth, width: 2, pad: '0'},
+	'b': {kind: elementMonthName},
+	'h': {kind: elementMonthName},
+	'B': {kind: elementMonthName, names: NameWide},
+	'd': {kind: elementDay, width: 2, pad: '0'},
+	'e': {kind: elementDay, width: 2, pad: ' '},
+	'j': {kind: elementDayOfYear, width: 3, pad: '0'},
+	'a': {kind: elementWeekdayName},
+	'A': {kind: elementWeekdayName, names: NameWide},
+	'u': {kind: elementWeekdayNumber, width: 1, alternate: true},
+	'w': {kind: elementWeekdayNumber, width: 1},
+	'H': {kind: elementHour, width: 2, pad: '0'},
+	'k': {kind: elementHour, width: 2, pad: ' '},
+	'I': {kind: elementHour12, width: 2, pad: '0'},
+	'l': {kind: elementHour12, width: 2, pad: ' '},
+	'M': {kind: elementMinute, width: 2, pad: '0'},
+	'S': {kind: elementSecond, width: 2, pad: '0'},
+	'L': {kind: elementFraction, width: 3},
+	'f': {kind: elementFraction, width: 6},
+	'N': {kind: elementFraction, width: 9},
+	'p': {kind: elementAMPM},
+	'P': {kind: elementAMPM, alternate: true},
+	'z': {kind: elementZoneOffset},
+	'Z': {kind: elementZoneName},
+}
+
+// Supports the common C, POSIX and GNU conversions, the GNU '-' (no padding),
+// '_' (space padding) and '0' (zero padding) flags, and %:z. The '!' flag
+// selects narrow month and weekday names (%!b, %!a). Unknown conversions are
+// kept as literal text.
+func compileStrftime(layout string) (elements []layoutElement) {
+	literalStart := 0
+	for i := 0; i < len(layout); {
+		if layout[i] != '%' || i+1 >= len(layout) {
+			i++
+			continue
+		}
+		elements = appendLiteral(elements, layout[literalStart:i])
+		j := i + 1
+		var flag byte
+		if layout[j] == '-' || layout[j] == '_' || layout[j] == '0' || layout[j] == ':' || layout[j] == '!' {
+			flag = layout[j]
+			j++
+		}
+		if j >= len(layout) {
+			literalStart = i
+			break
+		}
+
+		conversion := layout[j]
+		element, ok := strftimeConversions[conversion]
+		if flag == '!' && element.kind != elementMonthName && element.kind != elementWeekdayName {
+			ok = false
+		}
+		if ok {
+			switch flag {
+			case '-':
+				element.pad = 0
+			case '_':
+				element.pad = ' '
+			case '0':
+				element.pad = '0'
+			case ':':
+				element.colon = true
+			case '!':
+				element.names = NameNarrow
+			}
+			elements = append(elements, element)
+		} else if composite, ok := strftimeComposites[conversion]; ok && flag == 0 {
+			for _, element := range compileStrftime(composite) {
+				if element.kind == elementLiteral {
+					elements = appendLiteral(elements, element.text)
+				} else {
+					elements = append(elements, element)
+				}
+			}
+		} else {
+			switch {
+			case conversion == '%' && flag == 0:
+				elements = appendLiteral(elements, "%")
+			case conversion == 'n' && flag == 0:
+				elements = appendLiteral(elements, "\n")
+			case conversion == 't' && flag == 0:
+				elements = appendLiteral(elements, "\t")
+			default:
+				elements = appendLiteral(elements, layout[i:j+1])
+			}
+		}
+		i = j + 1
+		literalStart = i
+	}
+	return appendLiteral(elements, layout[literalStart:])
+}
+
+func appendNumber(dst []byte, value, width int, pad byte) []byte {
+	if value < 0 {
+		dst = append(dst, '-')
+		value = -value
+	}
+	digits := strconv.Itoa(value)
+	if pad != 0 {
+		for i := len(digits); i < width; i++ {
+			dst = append(dst, pad)
+		}
+	}
+	return append(dst, digits...)
+}
+
+// Day of the week, where 0 is Sunday.
+func weekday(year, month, day int) int {
+	_, remainder := floorDivMod(daysFromCivil(year, month, day)+4, 7)
+	return int(remainder)
+}
+
+func formatLayout[T Timestamp](t T, layout string, locale Locale) string {
+	if str, ok := formatInfinity(t); ok {
+		return str
+	}
+
+	var dst []byte
+	for _, e := range compileLayout(layout) {
+		switch e.kind {
+		case elementLiteral:
+			dst = append(dst, e.text...)
+		case elementYear:
+			dst = appendNumber(dst, t.Year(), e.width, e.pad)
+		case elementYearTwoDigit:
+			_, year := floorDivMod(int64(t.Year()), 100)
+			dst = appendNumber(dst, int(year), e.width, e.pad)
+		case elementMonth:
+			dst = appendNumber(dst, t.Month(), e.width, e.pad)
+		case elementMonthName:
+			if month := t.Month(); month >= 1 && month <= 12 {
+				dst = append(dst, locale.MonthName(month, e.names)...)
+			} else {
+				dst = append(dst, '?')
+			}
+		case elementDay:
+			dst = appendNumber(dst, t.Day(), e.width, e.pad)
+		case elementDayOfYear:
+			dst = appendNumber(dst, t.Doy(), e.width, e.pad)
+		case elementWeekdayName:
+			dst = append(dst, locale.WeekdayName(weekday(t.Year(), t.Month(), t.Day()), e.names)...)
+		case elementWeekdayNumber:
+			day := weekday(t.Year(), t.Month(), t.Day())
+			if e.alternate && day == 0 {
+				day = 7
+			}
+			dst = appendNumber(dst, day, e.width, e.pad)
+		case elementHour:
+			dst = appendNumber(dst, t.Hour(), e.width, e.pad)
+		case elementHour12:
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			dst = appendNumber(dst, hour, e.width, e.pad)
+		case elementMinute:
+			dst = appendNumber(dst, t.Minute(), e.width, e.pad)
+		case elementSecond:
+			dst = appendNumber(dst, t.Second(), e.width, e.pad)
+		case elementFraction:
+			digits := fmt.Sprintf("%09d", t.SubsecondNanos())[:e.width]
+			if e.trim {
+				if digits = strings.TrimRight(digits, "0"); digits == "" {
+					break
+				}
+			}
+			dst = append(append(dst, e.text...), digits...)
+		case elementAMPM:
+			ampm := locale.DayPeriodName(t.Hour() >= 12)
+			if e.alternate {
+				ampm = strings.ToLower(ampm)
+			}
+			dst = append(dst, ampm...)
+		case elementZoneOffset:
+			switch {
+			case e.zulu:
+				dst = append(dst, 'Z')
+			case e.hoursOnly:
+				dst = append(dst, "+00"...)
+			case e.colon:
+				dst = append(dst, "+00:00"...)
+			default:
+				dst = append(dst, "+0000"...)
+			}
+		case elementZoneName:
+			dst = append(dst, "UTC"...)
+		}
+	}
+	return string(dst)
+}
+
+// Format renders the value using a custom layout, reading the fields directly
+// (so a leap second renders as second 60, and years beyond 9999 in full). A
+// layout containing '%' is a strftime layout ("%Y%m%d-%H%M%S"); anything else
+// is a Go reference layout ("Mon, 02 Jan 2006 15:04:05 MST"). Values are
+// always in UTC. The infinity sentinels render as "infinity" and "-infinity".
+func (time Smalltime) Format(layout string) string {
+	return formatLayout(time, layout, englishLocale)
+}
+
+// FormatLocale is like Format, but takes month, weekday and AM/PM names from
+// locale. Combine it with the locale's DatePattern for a localized date.
+func (time Smalltime) FormatLocale(layout string, locale Locale) string {
+	return formatLayout(time, layout, locale)
+}
+
+// Format renders the value using a custom layout, reading the fields directly
+// (so a leap second renders as second 60). A layout containing '%' is a
+// strftime layout ("%Y%m%d-%H%M%S.%N"); anything else is a Go reference
+// layout ("Mon, 02 Jan 2006 15:04:05.000000000 MST"). Values are always in
+// UTC. The infinity sentinels render as "infinity" and "-infinity".
+func (time Nanotime) Format(layout string) string {
+	return formatLayout(time, layout, englishLocale)
+}
+
+// FormatLocale is like Format, but takes month, weekday and AM/PM names from
+// locale. Combine it with the locale's DatePattern for a localized date.
+func (time Nanotime) FormatLocale(layout string, locale Locale) string {
+	return formatLayout(time, layout, locale)
+}
+
+// ============================================================================
+
+type layoutParser struct {
+	str      string
+	position int
+}
+
+func (p *layoutParser) remaining() string {
+	return p.str[p.position:]
+}
+
+func (p *layoutParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("smalltime: cannot parse %q at offset %v: %v", p.str, p.position, fmt.Sprintf(format, args...))
+}
+
+func (p *layoutParser) number(minDigits, maxDigits int) (int, error) {
+	start := p.position
+	for p.position < len(p.str) && p.position-start < maxDigits && p.str[p.position] >= '0' && p.str[p.position] <= '9' {
+		p.position++
+	}
+	if p.position-start < minDigits {
+		return 0, p.errorf("expected %v digits", minDigits)
+	}
+	return strconv.Atoi(p.str[start:p.position])
+}
+
+func (p *layoutParser) paddedNumber(e layoutElement) (int, error) {
+	switch e.pad {
+	case '0':
+		return p.number(e.width, e.width)
+	case ' ':
+		for p.position < len(p.str) && p.str[p.position] == ' ' {
+			p.position++
+		}
+	}
+	return p.number(1, e.width)
+}
+
+// Matches the longest of the names case-insensitively, returning its index.
+func (p *layoutParser) name(names []string) (int, error) {
+	match, length := -1, 0
+	for i, name := range names {
+		if len(name) > length && len(p.remaining()) >= len(name) && strings.EqualFold(p.remaining()[:len(name)], name) {
+			match, length = i, len(name)
+		}
+	}
+	if match < 0 {
+		return 0, p.errorf("unrecognized name")
+	}
+	p.position += length
+	return match, nil
+}
+
+func (p *layoutParser) fraction(e layoutElement) (nanos int, err error) {
+	if e.text != "" {
+		rest := p.remaining()
+		if len(rest) < 2 || (rest[0] != '.' && rest[0] != ',') || rest[1] < '0' || rest[1] > '9' {
+			if e.trim {
+				return 0, nil
+			}
+			return 0, p.errorf("expected a fraction")
+		}
+		p.position++
+	}
+	start := p.position
+	if e.trim {
+		_, err = p.number(1, 9)
+	} else {
+		_, err = p.number(e.width, e.width)
+	}
+	if err != nil {
+		return 0, err
+	}
+	digits := p.str[start:p.position]
+	return strconv.Atoi(digits + strings.Repeat("0", 9-len(digits)))
+}
+
+func (p *layoutParser) zoneOffset(e layoutElement) (int, error) {
+	rest := p.remaining()
+	if len(rest) > 0 && (rest[0] == 'Z' || rest[0] == 'z') {
+		p.position++
+		return 0, nil
+	}
+	if len(rest) == 0 || (rest[0] != '+' && rest[0] != '-') {
+		return 0, p.errorf("expected a time zone offset")
+	}
+	p.position++
+	hours, err := p.number(2, 2)
+	if err != nil {
+		return 0, err
+	}
+	minutes := 0
+	if !e.hoursOnly {
+		if p.position < len(p.str) && p.str[p.position] == ':' {
+			p.position++
+		}
+		if minutes, err = p.number(2, 2); err != nil {
+			return 0, err
+		}
+		if err = checkRange("offset minute", minutes, 0, 59); err != nil {
+			return 0, err
+		}
+	}
+	offset := hours*60 + minutes
+	if rest[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// Reports whether the layout has its own fraction after the seconds, either as
+// a fraction element or as a literal separator (as in "%S.%f").
+func fractionFollows(elements []layoutElement) bool {
+	if len(elements) == 0 {
+		return false
+	}
+	next := elements[0]
+	return next.kind == elementFraction ||
+		(next.kind == elementLiteral && (next.text[0] == '.' || next.text[0] == ','))
+}
+
+func parseLayout(layout, str string, locale Locale) (f isoFields, err error) {
+	p := &layoutParser{str: str}
+	elements := compileLayout(layout)
+	f.month, f.day = 1, 1
+	hour12, pm, hasAMPM := 0, false, false
+	doy := 0
+	hasDate := false
+
+	for i, e := range elements {
+		switch e.kind {
+		case elementLiteral:
+			if !strings.HasPrefix(p.remaining(), e.text) {
+				return f, p.errorf("expected %q", e.text)
+			}
+			p.position += len(e.text)
+		case elementYear:
+			negative := false
+			if rest := p.remaining(); len(rest) > 0 && (rest[0] == '-' || rest[0] == '+') {
+				negative = rest[0] == '-'
+				p.position++
+			}
+			maxDigits := 9
+			if i+1 < len(elements) && elements[i+1].isNumeric() {
+				maxDigits = e.width
+			}
+			minDigits := 1
+			if e.pad != 0 {
+				minDigits = e.width
+			}
+			if f.year, err = p.number(minDigits, maxDigits); err != nil {
+				return f, err
+			}
+			if negative {
+				f.year = -f.year
+			}
+		case elementYearTwoDigit:
+			if f.year, err = p.number(2, 2); err != nil {
+				return f, err
+			}
+			if f.year >= 69 {
+				f.year += 1900
+			} else {
+				f.year += 2000
+			}
+		case elementMonth:
+			f.month, err = p.paddedNumber(e)
+			hasDate = true
+		case elementMonthName:
+			if e.names == NameNarrow {
+				return f, p.errorf("narrow month names can't be parsed")
+			}
+			f.month, err = p.name(monthNames(locale, e.names))
+			f.month++
+			hasDate = true
+		case elementDay:
+			f.day, err = p.paddedNumber(e)
+			hasDate = true
+		case elementDayOfYear:
+			doy, err = p.paddedNumber(e)
+		case elementWeekdayName:
+			// The weekday is implied by the date, so it's checked for syntax only.
+			if e.names == NameNarrow {
+				return f, p.errorf("narrow weekday names can't be parsed")
+			}
+			_, err = p.name(weekdayNames(locale, e.names))
+		case elementWeekdayNumber:
+			_, err = p.number(1, 1)
+		case elementHour:
+			f.hour, err = p.paddedNumber(e)
+		case elementHour12:
+			hour12, err = p.paddedNumber(e)
+			if err == nil && (hour12 < 1 || hour12 > 12) {
+				err = p.errorf("12-hour clock hour %v is out of range", hour12)
+			}
+		case elementMinute:
+			f.minute, err = p.paddedNumber(e)
+		case elementSecond:
+			if f.second, err = p.paddedNumber(e); err != nil {
+				return f, err
+			}
+			// As with the time package, a fraction may follow the seconds
+			// even if the layout doesn't have one.
+			if !fractionFollows(elements[i+1:]) {
+				f.nanos, err = p.fraction(layoutElement{kind: elementFraction, text: ".", trim: true})
+			}
+		case elementFraction:
+			f.nanos, err = p.fraction(e)
+		case elementAMPM:
+			hasAMPM = true
+			var index int
+			index, err = p.name([]string{locale.DayPeriodName(false), locale.DayPeriodName(true)})
+			pm = index == 1
+		case elementZoneOffset:
+			f.offsetMinutes, err = p.zoneOffset(e)
+		case elementZoneName:
+			_, err = p.name([]string{"UTC", "GMT", "Z"})
+		}
+		if err != nil {
+			return f, err
+		}
+	}
+	if p.position != len(str) {
+		return f, p.errorf("unexpected trailing characters")
+	}
+
+	if hour12 != 0 {
+		f.hour = hour12 % 12
+		if hasAMPM && pm {
+			f.hour += 12
+		}
+	}
+	if doy != 0 && !hasDate {
+		if err = checkRange("day of year", doy, 1, ymdToDoy(f.year, 12, 31)); err != nil {
+			return f, err
+		}
+		f.month, f.day = doyToYmd(f.year, doy)
+	}
+	return f, f.validate()
+}
+
+// ParseSmalltimeLayout parses a date & time using a custom layout (see
+// Smalltime.Format). Fields missing from the layout default to year 0, January
+// 1, 00:00:00. Time zone offsets are converted to UTC, but zone names other
+// than UTC and GMT are rejected. Weekdays are checked for syntax only. %L, %f
+// and %N (and Go's .000 forms) require exactly 3, 6 or 9 digits.
+func ParseSmalltimeLayout(layout, str string) (Smalltime, error) {
+	return ParseSmalltimeLocale(layout, str, englishLocale)
+}
+
+// ParseSmalltimeLocale is like ParseSmalltimeLayout, but matches month,
+// weekday and AM/PM names from locale.
+func ParseSmalltimeLocale(layout, str string, locale Locale) (Smalltime, error) {
+	switch parseInfinity(str) {
+	case 1:
+		return PositiveInfinitySmalltime, nil
+	case -1:
+		return NegativeInfinitySmalltime, nil
+	}
+	f, err := parseLayout(layout, str, locale)
+	if err != nil {
+		return 0, err
+	}
+	f.toUTC()
+	if err = checkRange("year", f.year, minYearSmalltime, maxYearSmalltime); err != nil {
+		return 0, err
+	}
+	return NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.nanos/1000), nil
+}
+
+// ParseNanotimeLayout parses a date & time using a custom layout (see
+// Nanotime.Format). Fields missing from the layout default to January 1,
+// 00:00:00, but the year is required. Time zone offsets are converted to UTC,
+// but zone names other than UTC and GMT are rejected. Weekdays are checked for
+// syntax only.
+func ParseNanotimeLayout(layout, str string) (Nanotime, error) {
+	return ParseNanotimeLocale(layout, str, englishLocale)
+}
+
+// ParseNanotimeLocale is like ParseNanotimeLayout, but matches month, weekday
+// and AM/PM names from locale.
+func ParseNanotimeLocale(layout, str string, locale Locale) (Nanotime, error) {
+	switch parseInfinity(str) {
+	case 1:
+		return PositiveInfinityNanotime, nil
+	case -1:
+		return NegativeInfinityNanotime, nil
+	}
+	f, err := parseLayout(layout, str, locale)
+	if err != nil {
+		return 0, err
+	}
+	f.toUTC()
+	if err = checkRange("year", f.year, minYearNanotime, maxYearNanotime); err != nil {
+		return 0, err
+	}
+	return NewNanotime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.nanos), nil
+}
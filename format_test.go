@@ -0,0 +1,152 @@
+package smalltime
+
+import (
+	"testing"
+	"time"
+)
+
+func assertLayout(t *testing.T, value Smalltime, layout, expected string) {
+	if actual := value.Format(layout); actual != expected {
+		t.Errorf("Expected %v formatted with %q to be %q but got %q", Format(value), layout, expected, actual)
+	}
+	// Layouts may drop fields, so check that parsing keeps everything present.
+	parsed, err := ParseSmalltimeLayout(layout, expected)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %q with %q: %v", expected, layout, err)
+	} else if reformatted := parsed.Format(layout); reformatted != expected {
+		t.Errorf("Expected %q parsed with %q to format the same but got %q", expected, layout, reformatted)
+	}
+}
+
+func assertParseLayout(t *testing.T, layout, str string, expected Smalltime) {
+	actual, err := ParseSmalltimeLayout(layout, str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %q with %q: %v", str, layout, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %q parsed with %q to be %v but got %v", str, layout, Format(expected), Format(actual))
+	}
+}
+
+func assertParseLayoutFails(t *testing.T, layout, str string) {
+	if actual, err := ParseSmalltimeLayout(layout, str); err == nil {
+		t.Errorf("Expected parsing %q with %q to fail but got %v", str, layout, Format(actual))
+	}
+}
+
+func TestFormatGoLayout(t *testing.T) {
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 9122)
+	assertLayout(t, value, "Mon, 02 Jan 2006 15:04:05 MST", "Mon, 20 May 2019 15:04:05 UTC")
+	assertLayout(t, value, "Monday, January 2, 2006 3:04:05.000000 PM", "Monday, May 20, 2019 3:04:05.009122 PM")
+	assertLayout(t, value, "2006-01-02T15:04:05.999999999Z07:00", "2019-05-20T15:04:05.009122Z")
+	assertLayout(t, value, "06/1/_2 03:04pm -0700", "19/5/20 03:04pm +0000")
+	assertLayout(t, value, "2006.002 15:04:05,000 -07:00", "2019.140 15:04:05,009 +00:00")
+	assertLayout(t, NewSmalltime(2019, 5, 7, 9, 0, 0, 0), "2006 __2 -07 Z07", "2019 127 +00 Z")
+	assertLayout(t, NewSmalltime(2019, 5, 7, 9, 0, 0, 0), "2006-01-_2", "2019-05- 7")
+	assertLayout(t, NewSmalltime(2019, 5, 7, 0, 0, 0, 0), "_2006 01 02 3PM", "_2019 05 07 12AM")
+
+	// Layouts go through the time package's reference values, not AsTime
+	assertLayout(t, NewSmalltime(2016, 12, 31, 23, 59, 60, 500000), time.RFC3339Nano, "2016-12-31T23:59:60.5Z")
+	assertLayout(t, NewSmalltime(12345, 6, 7, 0, 0, 0, 0), time.RFC3339, "12345-06-07T00:00:00Z")
+	assertLayout(t, NewSmalltime(-44, 3, 15, 0, 0, 0, 0), "2006-01-02 Mon", "-0044-03-15 Thu")
+}
+
+func TestFormatStrftime(t *testing.T) {
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 9122)
+	assertLayout(t, value, "%Y%m%d-%H%M%S", "20190520-150405")
+	assertLayout(t, value, "%a, %d %b %Y %T %z", "Mon, 20 May 2019 15:04:05 +0000")
+	assertLayout(t, value, "%A %B %e %Y %I:%M:%S.%f %p %Z", "Monday May 20 2019 03:04:05.009122 PM UTC")
+	assertLayout(t, value, "%F %T.%L %:z", "2019-05-20 15:04:05.009 +00:00")
+	assertLayout(t, value, "%D %R %j %u %w %P", "05/20/19 15:04 140 1 1 pm")
+	assertLayout(t, value, "%c", "Mon May 20 15:04:05 2019")
+	assertLayout(t, NewSmalltime(2019, 5, 7, 9, 0, 0, 0), "%-m/%-d %_H|%k|%l|%-I %% %n%t%Y", "5/7  9| 9| 9|9 % \n\t2019")
+	assertLayout(t, NewSmalltime(2019, 5, 5, 0, 0, 0, 0), "%F %u %w", "2019-05-05 7 0")
+	assertLayout(t, NewSmalltime(2016, 12, 31, 23, 59, 60, 0), "%Y%m%d%H%M%S", "20161231235960")
+	assertLayout(t, NewSmalltime(131071, 1, 1, 0, 0, 0, 0), "%Y-%m-%d", "131071-01-01")
+
+	if actual := value.Format("%Q %5"); actual != "%Q %5" {
+		t.Errorf("Expected unknown conversions to be kept but got %q", actual)
+	}
+}
+
+func TestFormatNanotimeLayout(t *testing.T) {
+	value := NewNanotime(2019, 5, 20, 15, 4, 5, 10159122)
+	if actual := value.Format("%Y%m%d %H%M%S.%N"); actual != "20190520 150405.010159122" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	if actual := value.Format(time.StampNano); actual != "May 20 15:04:05.010159122" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	parsed, err := ParseNanotimeLayout("%Y%m%d %H%M%S.%N", "20190520 150405.010159122")
+	if err != nil || parsed != value {
+		t.Errorf("Unexpected result %v (error %v)", Format(parsed), err)
+	}
+	if _, err = ParseNanotimeLayout("%m/%d", "05/20"); err == nil {
+		t.Errorf("Expected a missing year to be out of range for nanotime")
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	assertParseLayout(t, "2006-01-02 15:04:05 -0700", "2019-05-20 17:04:05 +0200", NewSmalltime(2019, 5, 20, 15, 4, 5, 0))
+	assertParseLayout(t, "%Y-%m-%dT%H:%M:%S%z", "2019-05-20T10:34:05-04:30", NewSmalltime(2019, 5, 20, 15, 4, 5, 0))
+	assertParseLayout(t, "%Y-%m-%dT%H:%M:%S%z", "2019-05-20T15:04:05Z", NewSmalltime(2019, 5, 20, 15, 4, 5, 0))
+	assertParseLayout(t, "2006-01-02 15:04:05", "2019-05-20 15:04:05.123", NewSmalltime(2019, 5, 20, 15, 4, 5, 123000))
+	assertParseLayout(t, "%Y %j", "2020 366", NewSmalltime(2020, 12, 31, 0, 0, 0, 0))
+	assertParseLayout(t, "%B %d %Y", "FEBRUARY 29 2000", NewSmalltime(2000, 2, 29, 0, 0, 0, 0))
+	assertParseLayout(t, "%y%m%d", "690101", NewSmalltime(1969, 1, 1, 0, 0, 0, 0))
+	assertParseLayout(t, "%y%m%d", "680101", NewSmalltime(2068, 1, 1, 0, 0, 0, 0))
+	assertParseLayout(t, "3:04 PM", "12:30 AM", NewSmalltime(0, 1, 1, 0, 30, 0, 0))
+	assertParseLayout(t, "3:04 PM", "12:30 PM", NewSmalltime(0, 1, 1, 12, 30, 0, 0))
+	assertParseLayout(t, "Jan _2 2006 MST", "Feb  3 2019 GMT", NewSmalltime(2019, 2, 3, 0, 0, 0, 0))
+	assertParseLayout(t, "%F", "infinity", PositiveInfinitySmalltime)
+	assertParseLayout(t, "%H%M%S%L%y", "15040512319", NewSmalltime(2019, 1, 1, 15, 4, 5, 123000))
+	assertParseLayout(t, "%S.%f%H", "05.00912215", NewSmalltime(0, 1, 1, 15, 0, 5, 9122))
+
+	assertParseLayoutFails(t, "%Y-%m-%d", "2019-02-29")
+	assertParseLayoutFails(t, "%Y-%m-%d", "2019-5-20")
+	assertParseLayoutFails(t, "%Y-%m-%d", "2019-05-20 ")
+	assertParseLayoutFails(t, "%Y %j", "2019 366")
+	assertParseLayoutFails(t, "%H:%M", "24:00")
+	assertParseLayoutFails(t, "%I %p", "13 PM")
+	assertParseLayoutFails(t, "%b %Y", "Foo 2019")
+	assertParseLayoutFails(t, "MST 2006", "EST 2019")
+	assertParseLayoutFails(t, "2006-01-02 15:04:05.000", "2019-05-20 15:04:05.12")
+	assertParseLayoutFails(t, "%H:%M%z", "15:04+0575")
+	assertParseLayoutFails(t, "15:04 -07:00", "15:04 +05:60")
+	assertParseLayoutFails(t, "%S.%f", "05.12")
+}
+
+func TestFormatLayoutInfinity(t *testing.T) {
+	if actual := NegativeInfinitySmalltime.Format("%F"); actual != "-infinity" {
+		t.Errorf("Expected -infinity but got %v", actual)
+	}
+	if actual := PositiveInfinityNanotime.Format(time.RFC3339); actual != "infinity" {
+		t.Errorf("Expected infinity but got %v", actual)
+	}
+}
+
+func TestCompiledLayoutCache(t *testing.T) {
+	layout := "%Y-%m-%d cached"
+	if first, second := compileLayout(layout), compileLayout(layout); &first[0] != &second[0] {
+		t.Errorf("Expected the compiled layout to be reused")
+	}
+
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	done := make(chan string)
+	for i := 0; i < 8; i++ {
+		go func() { done <- value.Format("02 Jan 2006 15:04") }()
+	}
+	for i := 0; i < 8; i++ {
+		if actual := <-done; actual != "20 May 2019 15:04" {
+			t.Errorf("Expected 20 May 2019 15:04 but got %v", actual)
+		}
+	}
+}
+
+func BenchmarkFormatLayout(b *testing.B) {
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	for i := 0; i < b.N; i++ {
+		value.Format(time.RFC1123)
+	}
+}
@@ -0,0 +1,330 @@
+package smalltime
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Maps any value into [min, max], so that fuzzers can explore only valid
+// field combinations.
+func wrap(value, min, max int) int {
+	span := max - min + 1
+	value = (value - min) % span
+	if value < 0 {
+		value += span
+	}
+	return value + min
+}
+
+type testFields struct {
+	year, month, day, hour, minute, second, subsecond int
+}
+
+func normalizeFields(year, month, day, hour, minute, second, subsecond, minYear, maxYear, maxSubsecond int) testFields {
+	f := testFields{year: wrap(year, minYear, maxYear), month: wrap(month, 1, 12)}
+	f.day = wrap(day, 1, daysInMonth(f.year, f.month))
+	f.hour = wrap(hour, 0, 23)
+	f.minute = wrap(minute, 0, 59)
+	f.second = wrap(second, 0, 60)
+	f.subsecond = wrap(subsecond, 0, maxSubsecond)
+	return f
+}
+
+func (f testFields) compare(other testFields) int {
+	a := []int{f.year, f.month, f.day, f.hour, f.minute, f.second, f.subsecond}
+	b := []int{other.year, other.month, other.day, other.hour, other.minute, other.second, other.subsecond}
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	}
+	if a.After(b) {
+		return 1
+	}
+	return 0
+}
+
+func smalltimeFields(year, month, day, hour, minute, second, microsecond int) testFields {
+	return normalizeFields(year, month, day, hour, minute, second, microsecond, -131072, 131071, 999999)
+}
+
+func nanotimeFields(year, month, day, hour, minute, second, nanosecond int) testFields {
+	return normalizeFields(year, month, day, hour, minute, second, nanosecond, 1970, 2225, 999999999)
+}
+
+func (f testFields) smalltime() Smalltime {
+	return NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.subsecond)
+}
+
+func (f testFields) nanotime() Nanotime {
+	return NewNanotime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.subsecond)
+}
+
+func addFieldSeeds(f *testing.F, format string) {
+	for _, v := range loadTestVectors(f) {
+		if v.Valid && v.Format == format {
+			f.Add(v.Year, v.Month, v.Day, v.Hour, v.Minute, v.Second, v.Subsecond)
+		}
+	}
+}
+
+func addRawSeeds(f *testing.F, format string) {
+	for _, v := range loadTestVectors(f) {
+		if v.Format == format {
+			raw, err := strconv.ParseUint(v.Raw, 0, 64)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(raw)
+		}
+	}
+	f.Add(uint64(0))
+	f.Add(^uint64(0))
+	f.Add(uint64(1) << 63)
+}
+
+func FuzzSmalltimeRoundTrip(f *testing.F) {
+	addFieldSeeds(f, "smalltime")
+	f.Fuzz(func(t *testing.T, year, month, day, hour, minute, second, microsecond int) {
+		fields := smalltimeFields(year, month, day, hour, minute, second, microsecond)
+		value := fields.smalltime()
+		decoded := testFields{value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Microsecond()}
+		if decoded != fields {
+			t.Fatalf("Encoded %v but decoded %v", fields, decoded)
+		}
+		if err := value.Validate(); err != nil {
+			t.Fatalf("%v: %v", fields, err)
+		}
+		if doy := ymdToDoy(fields.year, fields.month, fields.day); value.Doy() != doy {
+			t.Fatalf("%v: expected doy %v but got %v", fields, doy, value.Doy())
+		}
+		if withDoy := NewSmalltimeWithDoy(fields.year, value.Doy(), fields.hour, fields.minute, fields.second, fields.subsecond); withDoy != value {
+			t.Fatalf("%v: NewSmalltimeWithDoy gave %v", fields, Format(withDoy))
+		}
+		if parsed, err := ParseSmalltime(Format(value)); err != nil || parsed != value {
+			t.Fatalf("%v: %v parsed to %v (error %v)", fields, Format(value), Format(parsed), err)
+		}
+		if fields.second < 60 {
+			if fromTime := SmalltimeFromTime(value.AsTime()); fromTime != value {
+				t.Fatalf("%v: AsTime -> SmalltimeFromTime gave %v", fields, Format(fromTime))
+			}
+		}
+	})
+}
+
+func FuzzNanotimeRoundTrip(f *testing.F) {
+	addFieldSeeds(f, "nanotime")
+	f.Fuzz(func(t *testing.T, year, month, day, hour, minute, second, nanosecond int) {
+		fields := nanotimeFields(year, month, day, hour, minute, second, nanosecond)
+		value := fields.nanotime()
+		decoded := testFields{value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Nanosecond()}
+		if decoded != fields {
+			t.Fatalf("Encoded %v but decoded %v", fields, decoded)
+		}
+		if err := value.Validate(); err != nil {
+			t.Fatalf("%v: %v", fields, err)
+		}
+		if doy := ymdToDoy(fields.year, fields.month, fields.day); value.Doy() != doy {
+			t.Fatalf("%v: expected doy %v but got %v", fields, doy, value.Doy())
+		}
+		if withDoy := NewNanotimeWithDoy(fields.year, value.Doy(), fields.hour, fields.minute, fields.second, fields.subsecond); withDoy != value {
+			t.Fatalf("%v: NewNanotimeWithDoy gave %v", fields, Format(withDoy))
+		}
+		if parsed, err := ParseNanotime(Format(value)); err != nil || parsed != value {
+			t.Fatalf("%v: %v parsed to %v (error %v)", fields, Format(value), Format(parsed), err)
+		}
+		if fields.second < 60 {
+			if fromTime := NanotimeFromTime(value.AsTime()); fromTime != value {
+				t.Fatalf("%v: AsTime -> NanotimeFromTime gave %v", fields, Format(fromTime))
+			}
+		}
+	})
+}
+
+func FuzzSmalltimeOrdering(f *testing.F) {
+	f.Add(-1, 12, 31, 23, 59, 59, 999999, 0, 1, 1, 0, 0, 0, 0)
+	f.Add(2016, 12, 31, 23, 59, 60, 0, 2017, 1, 1, 0, 0, 0, 0)
+	f.Add(-131072, 1, 1, 0, 0, 0, 0, 131071, 12, 31, 23, 59, 60, 999999)
+	f.Add(1999, 2, 15, 12, 8, 45, 9122, 1999, 2, 15, 12, 8, 45, 9122)
+	f.Fuzz(func(t *testing.T, y1, mo1, d1, h1, mi1, s1, us1, y2, mo2, d2, h2, mi2, s2, us2 int) {
+		a := smalltimeFields(y1, mo1, d1, h1, mi1, s1, us1)
+		b := smalltimeFields(y2, mo2, d2, h2, mi2, s2, us2)
+		expected := a.compare(b)
+		encodedA, encodedB := a.smalltime(), b.smalltime()
+		actual := 0
+		if encodedA < encodedB {
+			actual = -1
+		} else if encodedA > encodedB {
+			actual = 1
+		}
+		if actual != expected {
+			t.Fatalf("%v vs %v: expected encoded order %v but got %v", a, b, expected, actual)
+		}
+		if a.second < 60 && b.second < 60 && compareTimes(encodedA.AsTime(), encodedB.AsTime()) != expected {
+			t.Fatalf("%v vs %v: encoded order doesn't match chronological order", a, b)
+		}
+	})
+}
+
+func FuzzNanotimeOrdering(f *testing.F) {
+	f.Add(2016, 12, 31, 23, 59, 60, 999999999, 2017, 1, 1, 0, 0, 0, 0)
+	f.Add(1970, 1, 1, 0, 0, 0, 0, 2225, 12, 31, 23, 59, 60, 999999999)
+	f.Add(1999, 2, 15, 12, 8, 45, 9122, 1999, 2, 15, 12, 8, 45, 9123)
+	f.Fuzz(func(t *testing.T, y1, mo1, d1, h1, mi1, s1, ns1, y2, mo2, d2, h2, mi2, s2, ns2 int) {
+		a := nanotimeFields(y1, mo1, d1, h1, mi1, s1, ns1)
+		b := nanotimeFields(y2, mo2, d2, h2, mi2, s2, ns2)
+		expected := a.compare(b)
+		encodedA, encodedB := a.nanotime(), b.nanotime()
+		actual := 0
+		if encodedA < encodedB {
+			actual = -1
+		} else if encodedA > encodedB {
+			actual = 1
+		}
+		if actual != expected {
+			t.Fatalf("%v vs %v: expected encoded order %v but got %v", a, b, expected, actual)
+		}
+		if a.second < 60 && b.second < 60 && compareTimes(encodedA.AsTime(), encodedB.AsTime()) != expected {
+			t.Fatalf("%v vs %v: encoded order doesn't match chronological order", a, b)
+		}
+	})
+}
+
+// Arbitrary raw values must never cause a panic, and any value that validates
+// must re-encode to itself.
+func FuzzSmalltimeRaw(f *testing.F) {
+	addRawSeeds(f, "smalltime")
+	f.Fuzz(func(t *testing.T, raw uint64) {
+		value := Smalltime(raw)
+		_ = value.Dump()
+		_ = Format(value)
+		if value.Validate() != nil {
+			return
+		}
+		if encoded := NewSmalltime(value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Microsecond()); encoded != value {
+			t.Fatalf("0x%016x re-encoded as 0x%016x", raw, uint64(encoded))
+		}
+		if parsed, err := ParseSmalltime(Format(value)); err != nil || parsed != value {
+			t.Fatalf("0x%016x: %v parsed to 0x%016x (error %v)", raw, Format(value), uint64(parsed), err)
+		}
+	})
+}
+
+func FuzzNanotimeRaw(f *testing.F) {
+	addRawSeeds(f, "nanotime")
+	f.Fuzz(func(t *testing.T, raw uint64) {
+		value := Nanotime(raw)
+		_ = value.Dump()
+		_ = Format(value)
+		if value.Validate() != nil {
+			return
+		}
+		if encoded := NewNanotime(value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Nanosecond()); encoded != value {
+			t.Fatalf("0x%016x re-encoded as 0x%016x", raw, uint64(encoded))
+		}
+		if parsed, err := ParseNanotime(Format(value)); err != nil || parsed != value {
+			t.Fatalf("0x%016x: %v parsed to 0x%016x (error %v)", raw, Format(value), uint64(parsed), err)
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	for _, v := range loadTestVectors(f) {
+		if v.Valid {
+			f.Add(v.ISO8601)
+		}
+	}
+	f.Add("2000-01-01T01:30:00+02:00")
+	f.Add("1999-12-31T19:30:00-0430")
+	f.Add("2016-12-31t18:59:60,123456789-05")
+	f.Add("-Infinity")
+	f.Fuzz(func(t *testing.T, str string) {
+		if value, err := ParseSmalltime(str); err == nil && !value.IsInfinite() {
+			if err = value.Validate(); err != nil {
+				t.Fatalf("%q parsed to invalid value 0x%016x: %v", str, uint64(value), err)
+			}
+		}
+		if value, err := ParseNanotime(str); err == nil && !value.IsInfinite() {
+			if err = value.Validate(); err != nil {
+				t.Fatalf("%q parsed to invalid value 0x%016x: %v", str, uint64(value), err)
+			}
+		}
+	})
+}
+
+// Covers every day of three full 400-year Gregorian cycles, including the
+// negative years before year zero.
+func TestDoyInversesOverCycles(t *testing.T) {
+	const firstYear, lastYear = -400, 799
+	previous := NewSmalltime(firstYear-1, 12, 31, 0, 0, 0, 0)
+	previousDays := daysFromCivil(firstYear-1, 12, 31)
+	for year := firstYear; year <= lastYear; year++ {
+		daysInYear := 365
+		if isLeapYear(year) {
+			daysInYear = 366
+		}
+		for doy := 1; doy <= daysInYear; doy++ {
+			month, day := doyToYmd(year, doy)
+			if actual := ymdToDoy(year, month, day); actual != doy {
+				t.Fatalf("Year %v doy %v -> %v-%v -> doy %v", year, doy, month, day, actual)
+			}
+			if day < 1 || day > daysInMonth(year, month) {
+				t.Fatalf("Year %v doy %v gave invalid date %v-%v", year, doy, month, day)
+			}
+
+			value := NewSmalltimeWithDoy(year, doy, 0, 0, 0, 0)
+			if value.Month() != month || value.Day() != day || value.Doy() != doy {
+				t.Fatalf("Year %v doy %v encoded as %v", year, doy, Format(value))
+			}
+			if value <= previous {
+				t.Fatalf("%v did not encode after %v", Format(value), Format(previous))
+			}
+			days := daysFromCivil(year, month, day)
+			if days != previousDays+1 {
+				t.Fatalf("%v is not the day after the previous one", Format(value))
+			}
+			if y, m, d := civilFromDays(days); y != year || m != month || d != day {
+				t.Fatalf("%v: civilFromDays gave %v-%v-%v", Format(value), y, m, d)
+			}
+			previous, previousDays = value, days
+		}
+	}
+	if cycleDays := daysFromCivil(400, 1, 1) - daysFromCivil(0, 1, 1); cycleDays != 146097 {
+		t.Errorf("Expected 146097 days in a 400-year cycle but got %v", cycleDays)
+	}
+}
+
+func TestAsTimeRoundTripEveryDay(t *testing.T) {
+	daysInYear := func(year int) int {
+		if isLeapYear(year) {
+			return 366
+		}
+		return 365
+	}
+	for year := 1970; year <= 2225; year++ {
+		for doy := 1; doy <= daysInYear(year); doy++ {
+			value := NewNanotimeWithDoy(year, doy, 23, 59, 59, 999999999)
+			if fromTime := NanotimeFromTime(value.AsTime()); fromTime != value {
+				t.Fatalf("%v round-tripped through time.Time as %v", Format(value), Format(fromTime))
+			}
+		}
+		smallYear := year - 2000
+		for doy := 1; doy <= daysInYear(smallYear); doy++ {
+			small := NewSmalltimeWithDoy(smallYear, doy, 12, 30, 0, 1)
+			if fromTime := SmalltimeFromTime(small.AsTime()); fromTime != small {
+				t.Fatalf("%v round-tripped through time.Time as %v", Format(small), Format(fromTime))
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package smalltime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts of the three HTTP-date forms from RFC 9110 section 5.6.7.
+const (
+	layoutIMFFixdate = "Mon, 02 Jan 2006 15:04:05 GMT"
+	layoutRFC850     = "Monday, 02-Jan-06 15:04:05 GMT"
+	layoutAsctime    = "Mon Jan _2 15:04:05 2006"
+	layoutRFC2822    = "Mon, 02 Jan 2006 15:04:05 -0700"
+)
+
+// FormatHTTPDate renders the value as an RFC 9110 IMF-fixdate, such as
+// "Sun, 06 Nov 1994 08:49:37 GMT". The sub-second portion is dropped, and the
+// year should be within 0000-9999 for the result to be a valid HTTP-date.
+func (time Smalltime) FormatHTTPDate() string {
+	return time.Format(layoutIMFFixdate)
+}
+
+// ParseHTTPDate parses any of the three HTTP-date forms that RFC 9110
+// requires recipients to accept: IMF-fixdate ("Sun, 06 Nov 1994 08:49:37
+// GMT"), the obsolete RFC 850 form ("Sunday, 06-Nov-94 08:49:37 GMT") and
+// the asctime form ("Sun Nov  6 08:49:37 1994"). As RFC 9110 requires, an
+// RFC 850 two-digit year that appears to be more than 50 years in the future
+// is taken to be in the past.
+func ParseHTTPDate(str string) (Smalltime, error) {
+	return parseHTTPDate(str, time.Now().UTC().Year())
+}
+
+func parseHTTPDate(str string, currentYear int) (Smalltime, error) {
+	f, err := parseLayout(layoutIMFFixdate, str, englishLocale)
+	if err != nil {
+		if f, err = parseLayout(layoutRFC850, str, englishLocale); err == nil {
+			f.year = resolveTwoDigitYear(f.year%100, currentYear)
+			err = f.validate()
+		} else {
+			f, err = parseLayout(layoutAsctime, str, englishLocale)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("smalltime: %q is not a valid HTTP-date", str)
+	}
+	return NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, 0), nil
+}
+
+func resolveTwoDigitYear(twoDigitYear, currentYear int) int {
+	year := currentYear - currentYear%100 + twoDigitYear
+	if year > currentYear+50 {
+		year -= 100
+	}
+	return year
+}
+
+// FormatRFC2822 renders the value as an RFC 2822 (RFC 5322) email date in UTC,
+// such as "Mon, 20 May 2019 15:04:05 +0000". The sub-second portion is
+// dropped.
+func (time Smalltime) FormatRFC2822() string {
+	return time.Format(layoutRFC2822)
+}
+
+// Obsolete zone names from RFC 5322 section 4.3, as offsets in minutes.
+var rfc2822Zones = map[string]int{
+	"UT": 0, "GMT": 0,
+	"EST": -5 * 60, "EDT": -4 * 60,
+	"CST": -6 * 60, "CDT": -5 * 60,
+	"MST": -7 * 60, "MDT": -6 * 60,
+	"PST": -8 * 60, "PDT": -7 * 60,
+}
+
+// Replaces (possibly nested) comments with spaces.
+func stripComments(str string) (string, error) {
+	var sb strings.Builder
+	depth := 0
+	for i := 0; i < len(str); i++ {
+		switch ch := str[i]; {
+		case ch == '\\' && depth > 0:
+			i++
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth == 0 {
+				return "", fmt.Errorf("unbalanced ')'")
+			}
+			depth--
+			if depth == 0 {
+				sb.WriteByte(' ')
+			}
+		case depth == 0:
+			sb.WriteByte(ch)
+		}
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unterminated comment")
+	}
+	return sb.String(), nil
+}
+
+// ParseRFC2822 parses an RFC 2822 (RFC 5322) email date such as
+// "Mon, 20 May 2019 17:04:05 +0200" or "20 May 2019 11:04 EDT", converting it
+// to UTC. The optional day of the week, optional seconds, comments, and the
+// obsolete forms (two and three digit years, and zone names) are all
+// accepted. Military and unknown single-letter zones are taken to be UTC, as
+// RFC 5322 recommends.
+func ParseRFC2822(str string) (Smalltime, error) {
+	fail := func(reason string) (Smalltime, error) {
+		return 0, fmt.Errorf("smalltime: cannot parse RFC 2822 date %q: %v", str, reason)
+	}
+
+	stripped, err := stripComments(str)
+	if err != nil {
+		return fail(err.Error())
+	}
+	fields := strings.Fields(strings.Replace(stripped, ",", " , ", 1))
+	if len(fields) > 1 && fields[1] == "," {
+		p := &layoutParser{str: fields[0]}
+		if _, err := p.name(weekdayNames(englishLocale, NameAbbreviated)); err != nil || p.position != len(fields[0]) {
+			return fail("invalid day of the week")
+		}
+		fields = fields[2:]
+	}
+	if len(fields) != 5 {
+		return fail("expected day, month, year, time and zone")
+	}
+
+	var f isoFields
+	if f.day, err = parseDigits(fields[0], 1, 2); err != nil {
+		return fail("invalid day")
+	}
+	p := &layoutParser{str: fields[1]}
+	if f.month, err = p.name(monthNames(englishLocale, NameAbbreviated)); err != nil || p.position != len(fields[1]) {
+		return fail("invalid month")
+	}
+	f.month++
+	if f.year, err = parseDigits(fields[2], 2, 9); err != nil {
+		return fail("invalid year")
+	}
+	switch len(fields[2]) {
+	case 2:
+		if f.year < 50 {
+			f.year += 2000
+		} else {
+			f.year += 1900
+		}
+	case 3:
+		f.year += 1900
+	}
+
+	clock := strings.Split(fields[3], ":")
+	if len(clock) < 2 || len(clock) > 3 {
+		return fail("invalid time")
+	}
+	values := []*int{&f.hour, &f.minute, &f.second}
+	for i, part := range clock {
+		if *values[i], err = parseDigits(part, 2, 2); err != nil {
+			return fail("invalid time")
+		}
+	}
+
+	zone := fields[4]
+	if offset, ok := rfc2822Zones[strings.ToUpper(zone)]; ok {
+		f.offsetMinutes = offset
+	} else if len(zone) == 1 && (zone[0]|0x20) >= 'a' && (zone[0]|0x20) <= 'z' {
+		f.offsetMinutes = 0
+	} else if len(zone) == 5 && (zone[0] == '+' || zone[0] == '-') {
+		hhmm, err := parseDigits(zone[1:], 4, 4)
+		if err != nil || hhmm%100 > 59 {
+			return fail("invalid zone")
+		}
+		f.offsetMinutes = hhmm/100*60 + hhmm%100
+		if zone[0] == '-' {
+			f.offsetMinutes = -f.offsetMinutes
+		}
+	} else {
+		return fail("invalid zone")
+	}
+
+	if err = f.validate(); err != nil {
+		return 0, err
+	}
+	f.toUTC()
+	if err = checkRange("year", f.year, minYearSmalltime, maxYearSmalltime); err != nil {
+		return 0, err
+	}
+	return NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, 0), nil
+}
+
+// Parses a string made up entirely of between minDigits and maxDigits digits.
+func parseDigits(str string, minDigits, maxDigits int) (int, error) {
+	p := &layoutParser{str: str}
+	value, err := p.number(minDigits, maxDigits)
+	if err == nil && p.position != len(str) {
+		err = p.errorf("unexpected trailing characters")
+	}
+	return value, err
+}
@@ -0,0 +1,114 @@
+package smalltime
+
+import "testing"
+
+func assertHTTPDate(t *testing.T, str string, currentYear int, expected Smalltime) {
+	actual, err := parseHTTPDate(str, currentYear)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %q: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %q to parse as %v but got %v", str, Format(expected), Format(actual))
+	}
+}
+
+func assertRFC2822(t *testing.T, str string, expected Smalltime) {
+	actual, err := ParseRFC2822(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %q: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %q to parse as %v but got %v", str, Format(expected), Format(actual))
+	}
+}
+
+func TestFormatHTTPDate(t *testing.T) {
+	value := NewSmalltime(1994, 11, 6, 8, 49, 37, 123456)
+	if actual := value.FormatHTTPDate(); actual != "Sun, 06 Nov 1994 08:49:37 GMT" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	if actual := NewSmalltime(2016, 12, 31, 23, 59, 60, 0).FormatHTTPDate(); actual != "Sat, 31 Dec 2016 23:59:60 GMT" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+}
+
+func TestParseHTTPDate(t *testing.T) {
+	expected := NewSmalltime(1994, 11, 6, 8, 49, 37, 0)
+	assertHTTPDate(t, "Sun, 06 Nov 1994 08:49:37 GMT", 2019, expected)
+	assertHTTPDate(t, "Sunday, 06-Nov-94 08:49:37 GMT", 2019, expected)
+	assertHTTPDate(t, "Sun Nov  6 08:49:37 1994", 2019, expected)
+
+	// RFC 850 years more than 50 years in the future are in the past
+	assertHTTPDate(t, "Thursday, 01-Jan-70 00:00:00 GMT", 2019, NewSmalltime(1970, 1, 1, 0, 0, 0, 0))
+	assertHTTPDate(t, "Friday, 01-Jan-60 00:00:00 GMT", 2019, NewSmalltime(2060, 1, 1, 0, 0, 0, 0))
+	assertHTTPDate(t, "Tuesday, 01-Jan-80 00:00:00 GMT", 2030, NewSmalltime(2080, 1, 1, 0, 0, 0, 0))
+	assertHTTPDate(t, "Monday, 01-Jan-81 00:00:00 GMT", 2030, NewSmalltime(1981, 1, 1, 0, 0, 0, 0))
+
+	for _, str := range []string{
+		"",
+		"Sun, 06 Nov 1994 08:49:37 +0000",
+		"Sun, 06 Nov 1994 08:49:37",
+		"Sun, 31 Nov 1994 08:49:37 GMT",
+		"1994-11-06T08:49:37Z",
+		"Tuesday, 29-Feb-01 00:00:00 GMT",
+	} {
+		if actual, err := parseHTTPDate(str, 2019); err == nil {
+			t.Errorf("Expected %q to fail but got %v", str, Format(actual))
+		}
+	}
+
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	if actual, err := ParseHTTPDate(value.FormatHTTPDate()); err != nil || actual != value {
+		t.Errorf("Expected a round trip but got %v (error %v)", Format(actual), err)
+	}
+}
+
+func TestFormatRFC2822(t *testing.T) {
+	value := NewSmalltime(2019, 5, 20, 15, 4, 5, 9122)
+	if actual := value.FormatRFC2822(); actual != "Mon, 20 May 2019 15:04:05 +0000" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	if actual, err := ParseRFC2822(value.FormatRFC2822()); err != nil || actual != NewSmalltime(2019, 5, 20, 15, 4, 5, 0) {
+		t.Errorf("Expected a round trip but got %v (error %v)", Format(actual), err)
+	}
+}
+
+func TestParseRFC2822(t *testing.T) {
+	expected := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	assertRFC2822(t, "Mon, 20 May 2019 15:04:05 +0000", expected)
+	assertRFC2822(t, "Mon, 20 May 2019 17:04:05 +0200", expected)
+	assertRFC2822(t, "Mon, 20 May 2019 10:34:05 -0430", expected)
+	assertRFC2822(t, "20 May 2019 11:04:05 EDT", expected)
+	assertRFC2822(t, "mon,20 may 2019 08:04:05 pdt", expected)
+	assertRFC2822(t, "Mon, 20 May 19 15:04:05 GMT", expected)
+	assertRFC2822(t, "Mon, 20 May 119 15:04:05 UT", expected)
+	assertRFC2822(t, "Mon (Monday), 20 May 2019 15:04:05 +0000 (Coordinated (Universal) Time)", expected)
+	assertRFC2822(t, "  Mon,  20\r\n May 2019 15:04:05 Z ", expected)
+	assertRFC2822(t, "Mon, 20 May 2019 15:04 +0000", NewSmalltime(2019, 5, 20, 15, 4, 0, 0))
+	assertRFC2822(t, "Mon, 1 Jan 99 00:00:00 +0000", NewSmalltime(1999, 1, 1, 0, 0, 0, 0))
+	assertRFC2822(t, "Sat, 1 Jan 2000 00:30:00 +0100", NewSmalltime(1999, 12, 31, 23, 30, 0, 0))
+	assertRFC2822(t, "Sat, 31 Dec 2016 23:59:60 +0000", NewSmalltime(2016, 12, 31, 23, 59, 60, 0))
+
+	for _, str := range []string{
+		"",
+		"Mon, 20 May 2019",
+		"Foo, 20 May 2019 15:04:05 +0000",
+		"Mon, 20 Foo 2019 15:04:05 +0000",
+		"Mon, 32 May 2019 15:04:05 +0000",
+		"Mon, 20 May 2019 15:04:05",
+		"Mon, 20 May 2019 15:04:05 +00",
+		"Mon, 20 May 2019 15:04:05 +0060",
+		"Mon, 20 May 2019 15:4:05 +0000",
+		"Mon, 20 May 2019 24:00:00 +0000",
+		"Mon, 20 May 2019 15:04:05 XYZ",
+		"Mon, 20 May 2019 15:04:05 +0000 (unterminated",
+		"Mon, 20 May 2019 15:04:05 +0000 extra",
+		"2019-05-20T15:04:05Z",
+	} {
+		if actual, err := ParseRFC2822(str); err == nil {
+			t.Errorf("Expected %q to fail but got %v", str, Format(actual))
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package smalltime
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ID is a 128-bit sortable unique identifier, similar to a ULID: a big-endian
+// Nanotime followed by 64 bits of entropy. IDs sort by creation time, and the
+// timestamp's date fields can be read directly from the ID.
+//
+// The text form is 26 Crockford base32 characters (as used by EncodeSortable),
+// which also sorts by creation time.
+type ID [16]byte
+
+// SmalltimeID is like ID, but with a Smalltime prefix, trading nanosecond
+// precision for Smalltime's much larger range of years. The prefix's sign bit
+// is flipped so that IDs from before year 0 still sort first.
+type SmalltimeID [16]byte
+
+const idTextLength = 26
+
+var ErrInvalidID = errors.New("smalltime: invalid ID")
+var ErrIDOverflow = errors.New("smalltime: ID entropy overflow within a single tick")
+
+const smalltimeIDSignBit = 1 << 63
+
+func NewIDFromParts(t Nanotime, entropy uint64) ID {
+	return newIDFromParts(uint64(t), entropy)
+}
+
+func NewSmalltimeIDFromParts(t Smalltime, entropy uint64) SmalltimeID {
+	return newIDFromParts(uint64(t)^smalltimeIDSignBit, entropy)
+}
+
+func newIDFromParts(prefix, entropy uint64) [16]byte {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], prefix)
+	binary.BigEndian.PutUint64(id[8:], entropy)
+	return id
+}
+
+// IDGenerator generates IDs that increase monotonically: IDs generated within
+// the same nanosecond tick (or while the clock is behind the last ID) reuse the
+// last timestamp and increment the entropy instead of drawing new randomness.
+type IDGenerator struct {
+	mutex   sync.Mutex
+	entropy io.Reader
+	now     func() time.Time
+	last    ID
+}
+
+// NewIDGenerator creates a generator that reads entropy from the given reader
+// and the current time from now. If either is nil, crypto/rand.Reader and
+// time.Now are used.
+func NewIDGenerator(entropy io.Reader, now func() time.Time) *IDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &IDGenerator{entropy: entropy, now: now}
+}
+
+func (g *IDGenerator) New() (ID, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	id, err := nextID(g.last, uint64(NanotimeFromTime(g.now())), g.entropy)
+	if err != nil {
+		return ID{}, err
+	}
+	g.last = id
+	return g.last, nil
+}
+
+// SmalltimeIDGenerator is like IDGenerator, but generates SmalltimeIDs, whose
+// tick is a microsecond.
+type SmalltimeIDGenerator struct {
+	mutex   sync.Mutex
+	entropy io.Reader
+	now     func() time.Time
+	last    SmalltimeID
+}
+
+// NewSmalltimeIDGenerator creates a generator that reads entropy from the
+// given reader and the current time from now. If either is nil,
+// crypto/rand.Reader and time.Now are used.
+func NewSmalltimeIDGenerator(entropy io.Reader, now func() time.Time) *SmalltimeIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &SmalltimeIDGenerator{entropy: entropy, now: now}
+}
+
+func (g *SmalltimeIDGenerator) New() (SmalltimeID, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	id, err := nextID(g.last, uint64(SmalltimeFromTime(g.now()))^smalltimeIDSignBit, g.entropy)
+	if err != nil {
+		return SmalltimeID{}, err
+	}
+	g.last = id
+	return g.last, nil
+}
+
+// Returns the ID following last for a time prefix. If the prefix hasn't moved
+// past last's, last's prefix is reused and its entropy incremented.
+func nextID(last [16]byte, prefix uint64, entropy io.Reader) ([16]byte, error) {
+	if lastPrefix := binary.BigEndian.Uint64(last[:8]); prefix <= lastPrefix && last != ([16]byte{}) {
+		next := binary.BigEndian.Uint64(last[8:]) + 1
+		if next == 0 {
+			return [16]byte{}, ErrIDOverflow
+		}
+		return newIDFromParts(lastPrefix, next), nil
+	}
+
+	var random [8]byte
+	if _, err := io.ReadFull(entropy, random[:]); err != nil {
+		return [16]byte{}, err
+	}
+	return newIDFromParts(prefix, binary.BigEndian.Uint64(random[:])), nil
+}
+
+var defaultIDGenerator = NewIDGenerator(nil, nil)
+var defaultSmalltimeIDGenerator = NewSmalltimeIDGenerator(nil, nil)
+
+// NewID generates an ID using crypto/rand and the system clock. It panics if
+// crypto/rand fails.
+func NewID() ID {
+	id, err := defaultIDGenerator.New()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// NewSmalltimeID generates a SmalltimeID using crypto/rand and the system
+// clock. It panics if crypto/rand fails.
+func NewSmalltimeID() SmalltimeID {
+	id, err := defaultSmalltimeIDGenerator.New()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (id ID) Nanotime() Nanotime {
+	return Nanotime(binary.BigEndian.Uint64(id[:8]))
+}
+
+func (id ID) Time() time.Time {
+	return id.Nanotime().AsTime()
+}
+
+func (id ID) Entropy() uint64 {
+	return binary.BigEndian.Uint64(id[8:])
+}
+
+func (id ID) String() string {
+	return string(appendIDText(nil, id))
+}
+
+// The 128 bits are encoded as 130 bits with 2 leading zero bits.
+func appendIDText(dst []byte, id [16]byte) []byte {
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+	var text [idTextLength]byte
+	for i := idTextLength - 1; i >= 0; i-- {
+		text[i] = sortableAlphabet[low&0x1f]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return append(dst, text[:]...)
+}
+
+func ParseID(str string) (ID, error) {
+	return parseIDText(str)
+}
+
+func parseIDText(str string) ([16]byte, error) {
+	if len(str) != idTextLength {
+		return [16]byte{}, fmt.Errorf("%w: length %v (expected %v)", ErrInvalidID, len(str), idTextLength)
+	}
+	var high, low uint64
+	for i := 0; i < len(str); i++ {
+		digit := sortableDecodeTable[str[i]]
+		if digit == 0xff || (i == 0 && digit > 7) {
+			return [16]byte{}, fmt.Errorf("%w: invalid character %q at index %v", ErrInvalidID, str[i], i)
+		}
+		high = high<<5 | low>>59
+		low = low<<5 | uint64(digit)
+	}
+	return newIDFromParts(high, low), nil
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return appendIDText(nil, id), nil
+}
+
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id ID) MarshalBinary() ([]byte, error) {
+	return id[:], nil
+}
+
+func (id *ID) UnmarshalBinary(data []byte) error {
+	return unmarshalIDBinary((*[16]byte)(id), data)
+}
+
+func unmarshalIDBinary(id *[16]byte, data []byte) error {
+	if len(data) != len(id) {
+		return fmt.Errorf("%w: binary length %v (expected %v)", ErrInvalidID, len(data), len(id))
+	}
+	copy(id[:], data)
+	return nil
+}
+
+// Value implements driver.Valuer, storing the ID in its 16 byte binary form.
+func (id ID) Value() (driver.Value, error) {
+	return id.MarshalBinary()
+}
+
+// Scan implements sql.Scanner, accepting the 16 byte binary form or the text
+// form (as a string or []byte).
+func (id *ID) Scan(src interface{}) error {
+	return scanID((*[16]byte)(id), src)
+}
+
+func scanID(id *[16]byte, src interface{}) (err error) {
+	switch src := src.(type) {
+	case []byte:
+		if len(src) == idTextLength {
+			*id, err = parseIDText(string(src))
+			return err
+		}
+		return unmarshalIDBinary(id, src)
+	case string:
+		*id, err = parseIDText(src)
+		return err
+	}
+	return fmt.Errorf("%w: cannot scan %T", ErrInvalidID, src)
+}
+
+// ============================================================================
+
+func (id SmalltimeID) Smalltime() Smalltime {
+	return Smalltime(binary.BigEndian.Uint64(id[:8]) ^ smalltimeIDSignBit)
+}
+
+func (id SmalltimeID) Time() time.Time {
+	return id.Smalltime().AsTime()
+}
+
+func (id SmalltimeID) Entropy() uint64 {
+	return binary.BigEndian.Uint64(id[8:])
+}
+
+func (id SmalltimeID) String() string {
+	return string(appendIDText(nil, id))
+}
+
+func ParseSmalltimeID(str string) (SmalltimeID, error) {
+	return parseIDText(str)
+}
+
+func (id SmalltimeID) MarshalText() ([]byte, error) {
+	return appendIDText(nil, id), nil
+}
+
+func (id *SmalltimeID) UnmarshalText(text []byte) error {
+	parsed, err := ParseSmalltimeID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id SmalltimeID) MarshalBinary() ([]byte, error) {
+	return id[:], nil
+}
+
+func (id *SmalltimeID) UnmarshalBinary(data []byte) error {
+	return unmarshalIDBinary((*[16]byte)(id), data)
+}
+
+// Value implements driver.Valuer, storing the ID in its 16 byte binary form.
+func (id SmalltimeID) Value() (driver.Value, error) {
+	return id.MarshalBinary()
+}
+
+// Scan implements sql.Scanner, accepting the 16 byte binary form or the text
+// form (as a string or []byte).
+func (id *SmalltimeID) Scan(src interface{}) error {
+	return scanID((*[16]byte)(id), src)
+}
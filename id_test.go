@@ -0,0 +1,230 @@
+package smalltime
+
+import "bytes"
+import "errors"
+import "encoding/json"
+import "math/rand"
+import "sort"
+import "testing"
+import "time"
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestIDParts(t *testing.T) {
+	ts := NewNanotime(2019, 5, 20, 14, 22, 1, 123456789)
+	id := NewIDFromParts(ts, 0x0123456789abcdef)
+	if id.Nanotime() != ts || id.Entropy() != 0x0123456789abcdef {
+		t.Errorf("Unexpected parts %016x %016x", id.Nanotime(), id.Entropy())
+	}
+	if id.Nanotime().Year() != 2019 || id.Nanotime().Day() != 20 {
+		t.Errorf("Expected date fields to be readable from the ID")
+	}
+	if !id.Time().Equal(time.Date(2019, 5, 20, 14, 22, 1, 123456789, time.UTC)) {
+		t.Errorf("Unexpected time %v", id.Time())
+	}
+}
+
+func TestIDText(t *testing.T) {
+	var zero ID
+	if zero.String() != "00000000000000000000000000" {
+		t.Errorf("Unexpected zero ID text %v", zero.String())
+	}
+	max := NewIDFromParts(^Nanotime(0), ^uint64(0))
+	if max.String() != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Unexpected max ID text %v", max.String())
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		a := NewIDFromParts(Nanotime(random.Uint64()), random.Uint64())
+		b := NewIDFromParts(Nanotime(random.Uint64()), random.Uint64())
+		parsed, err := ParseID(a.String())
+		if err != nil || parsed != a {
+			t.Fatalf("Expected %v to parse to %x but got %x (%v)", a.String(), a, parsed, err)
+		}
+		if (bytes.Compare(a[:], b[:]) < 0) != (a.String() < b.String()) {
+			t.Fatalf("Text order of %v and %v doesn't match binary order", a, b)
+		}
+	}
+
+	for _, str := range []string{"", "0000000000000000000000000", "80000000000000000000000000", "0000000000000000000000000U"} {
+		if _, err := ParseID(str); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("Expected %q to be rejected but got %v", str, err)
+		}
+	}
+}
+
+func TestIDMonotonic(t *testing.T) {
+	clock := time.Date(2019, 5, 20, 14, 22, 1, 0, time.UTC)
+	generator := NewIDGenerator(bytes.NewReader(bytes.Repeat([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0}, 10)),
+		func() time.Time { return clock })
+
+	first, err := generator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := first
+	for i := 0; i < 15; i++ {
+		id, err := generator.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.Nanotime() != first.Nanotime() || id.Entropy() != previous.Entropy()+1 {
+			t.Errorf("Expected same-tick ID to increment entropy, but got %v after %v", id, previous)
+		}
+		previous = id
+	}
+	if _, err := generator.New(); err != ErrIDOverflow {
+		t.Errorf("Expected ErrIDOverflow but got %v", err)
+	}
+
+	clock = clock.Add(-time.Second)
+	generator.last = first
+	id, err := generator.New()
+	if err != nil || id.Nanotime() != first.Nanotime() || id.String() <= first.String() {
+		t.Errorf("Expected ID to stay monotonic when the clock goes backwards, but got %v after %v (%v)", id, first, err)
+	}
+
+	clock = clock.Add(time.Hour)
+	id, err = generator.New()
+	if err != nil || id.Nanotime() != NanotimeFromTime(clock) {
+		t.Errorf("Expected ID at %v but got %v (%v)", clock, id.Time(), err)
+	}
+}
+
+func TestIDSortsByTime(t *testing.T) {
+	ids := make([]ID, 1000)
+	for i := range ids {
+		ids[i] = NewID()
+	}
+	sorted := append([]ID(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	for i := range ids {
+		if ids[i] != sorted[i] {
+			t.Fatalf("Expected IDs to be generated in sorted order")
+		}
+	}
+}
+
+func TestIDMarshaling(t *testing.T) {
+	generator := NewIDGenerator(rand.New(rand.NewSource(1)), fixedClock(time.Date(2019, 5, 20, 0, 0, 0, 0, time.UTC)))
+	id, err := generator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := json.Marshal(map[string]ID{"id": id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]ID
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded["id"] != id {
+		t.Errorf("Expected JSON %s to round trip to %v but got %v (%v)", encoded, id, decoded["id"], err)
+	}
+
+	binary, _ := id.MarshalBinary()
+	var fromBinary ID
+	if err := fromBinary.UnmarshalBinary(binary); err != nil || fromBinary != id {
+		t.Errorf("Expected binary round trip to %v but got %v (%v)", id, fromBinary, err)
+	}
+	if err := fromBinary.UnmarshalBinary(binary[:15]); err == nil {
+		t.Errorf("Expected a 15 byte binary ID to be rejected")
+	}
+
+	value, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []interface{}{value, id.String(), []byte(id.String())} {
+		var scanned ID
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("Expected %v to scan to %v but got %v (%v)", src, id, scanned, err)
+		}
+	}
+	var scanned ID
+	if err := scanned.Scan(42); err == nil {
+		t.Errorf("Expected scanning an int to fail")
+	}
+}
+
+func TestSmalltimeID(t *testing.T) {
+	ts := NewSmalltime(2019, 5, 20, 14, 22, 1, 123456)
+	id := NewSmalltimeIDFromParts(ts, 0x0123456789abcdef)
+	if id.Smalltime() != ts || id.Entropy() != 0x0123456789abcdef {
+		t.Errorf("Unexpected parts %016x %016x", id.Smalltime(), id.Entropy())
+	}
+	if !id.Time().Equal(time.Date(2019, 5, 20, 14, 22, 1, 123456000, time.UTC)) {
+		t.Errorf("Unexpected time %v", id.Time())
+	}
+
+	values := []Smalltime{MinSmalltime, NewSmalltime(-44, 3, 15, 0, 0, 0, 0), NewSmalltime(0, 1, 1, 0, 0, 0, 0), ts, MaxSmalltime}
+	for i := 1; i < len(values); i++ {
+		a := NewSmalltimeIDFromParts(values[i-1], ^uint64(0))
+		b := NewSmalltimeIDFromParts(values[i], 0)
+		if bytes.Compare(a[:], b[:]) >= 0 || a.String() >= b.String() {
+			t.Errorf("Expected the ID for %v to sort before the ID for %v", Format(values[i-1]), Format(values[i]))
+		}
+		parsed, err := ParseSmalltimeID(b.String())
+		if err != nil || parsed != b || parsed.Smalltime() != values[i] {
+			t.Errorf("Expected %v to parse to %x but got %x (%v)", b.String(), b, parsed, err)
+		}
+	}
+}
+
+func TestSmalltimeIDMonotonic(t *testing.T) {
+	clock := time.Date(2019, 5, 20, 14, 22, 1, 0, time.UTC)
+	generator := NewSmalltimeIDGenerator(bytes.NewReader(bytes.Repeat([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}, 10)),
+		func() time.Time { return clock })
+
+	first, err := generator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(time.Nanosecond)
+	second, err := generator.New()
+	if err != nil || second.Smalltime() != first.Smalltime() || second.Entropy() != first.Entropy()+1 {
+		t.Errorf("Expected same-tick ID to increment entropy, but got %v after %v (%v)", second, first, err)
+	}
+	if _, err := generator.New(); err != ErrIDOverflow {
+		t.Errorf("Expected ErrIDOverflow but got %v", err)
+	}
+
+	clock = clock.Add(time.Microsecond)
+	id, err := generator.New()
+	if err != nil || id.Smalltime() != SmalltimeFromTime(clock) || id.String() <= first.String() {
+		t.Errorf("Expected ID at %v but got %v (%v)", clock, id.Time(), err)
+	}
+
+	if a, b := NewSmalltimeID(), NewSmalltimeID(); a.String() >= b.String() {
+		t.Errorf("Expected %v to sort before %v", a, b)
+	}
+}
+
+func TestSmalltimeIDMarshaling(t *testing.T) {
+	id := NewSmalltimeIDFromParts(NewSmalltime(2019, 5, 20, 0, 0, 0, 0), 42)
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SmalltimeID
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != id {
+		t.Errorf("Expected JSON %s to round trip to %v but got %v (%v)", encoded, id, decoded, err)
+	}
+
+	value, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []interface{}{value, id.String(), []byte(id.String())} {
+		var scanned SmalltimeID
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("Expected %v to scan to %v but got %v (%v)", src, id, scanned, err)
+		}
+	}
+	var scanned SmalltimeID
+	if err := scanned.UnmarshalBinary(make([]byte, 15)); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected a 15 byte binary ID to be rejected but got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package smalltime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrUnanchoredInterval = errors.New("smalltime: interval has no start or end")
+
+// Interval is an ISO 8601 time interval in one of its four forms:
+//
+//	start/end           2019-05-20T00:00:00Z/2019-05-21T00:00:00Z
+//	start/duration      2019-05-20T00:00:00Z/P1D
+//	duration/end        P1D/2019-05-21T00:00:00Z
+//	duration            P1D
+//
+// Fields that aren't part of the form are zero (Start and End use the zero
+// Smalltime, which is never a valid date). Use Bounds to get the start and
+// end regardless of form.
+type Interval struct {
+	Start    Smalltime
+	End      Smalltime
+	Duration Period
+}
+
+// Bounds returns the start and end of the interval, computing whichever one
+// is given as a duration. A duration-only interval returns
+// ErrUnanchoredInterval.
+func (i Interval) Bounds() (start, end Smalltime, err error) {
+	switch {
+	case !i.Start.IsZero() && !i.End.IsZero():
+		return i.Start, i.End, nil
+	case !i.Start.IsZero():
+		end, err = i.Start.AddPeriod(i.Duration)
+		return i.Start, end, err
+	case !i.End.IsZero():
+		start, err = i.End.AddPeriod(i.Duration.Negate())
+		return start, i.End, err
+	}
+	return 0, 0, ErrUnanchoredInterval
+}
+
+// String formats the interval in the ISO 8601 form it was given in.
+func (i Interval) String() string {
+	switch {
+	case !i.Start.IsZero() && !i.End.IsZero():
+		return Format(i.Start) + "/" + Format(i.End)
+	case !i.Start.IsZero():
+		return Format(i.Start) + "/" + i.Duration.String()
+	case !i.End.IsZero():
+		return i.Duration.String() + "/" + Format(i.End)
+	}
+	return i.Duration.String()
+}
+
+func isPeriodString(str string) bool {
+	str = strings.TrimLeft(str, "+-")
+	return strings.HasPrefix(str, "P") || strings.HasPrefix(str, "p")
+}
+
+// ParseInterval parses an ISO 8601 time interval in any of its four forms
+// (see Interval). Dates & times are parsed by ParseSmalltime, and durations
+// by ParsePeriod. The end of a start/end interval must not be before its
+// start.
+func ParseInterval(str string) (Interval, error) {
+	var i Interval
+	parts := strings.Split(str, "/")
+	if len(parts) > 2 {
+		return i, fmt.Errorf("smalltime: cannot parse interval %q: too many '/' separators", str)
+	}
+
+	var err error
+	if len(parts) == 1 {
+		if !isPeriodString(parts[0]) {
+			return i, fmt.Errorf("smalltime: cannot parse interval %q: expected a duration", str)
+		}
+		i.Duration, err = ParsePeriod(parts[0])
+		return i, err
+	}
+
+	first, second := parts[0], parts[1]
+	switch {
+	case isPeriodString(first) && isPeriodString(second):
+		return i, fmt.Errorf("smalltime: cannot parse interval %q: both parts are durations", str)
+	case isPeriodString(first):
+		if i.Duration, err = ParsePeriod(first); err != nil {
+			return i, err
+		}
+		i.End, err = ParseSmalltime(second)
+	case isPeriodString(second):
+		if i.Start, err = ParseSmalltime(first); err != nil {
+			return i, err
+		}
+		i.Duration, err = ParsePeriod(second)
+	default:
+		if i.Start, err = ParseSmalltime(first); err != nil {
+			return i, err
+		}
+		if i.End, err = ParseSmalltime(second); err != nil {
+			return i, err
+		}
+		if i.End < i.Start {
+			return i, fmt.Errorf("smalltime: cannot parse interval %q: end is before start", str)
+		}
+	}
+	return i, err
+}
+
+// UnboundedRepetitions is the Repetitions value of an interval that repeats
+// forever (R/...).
+const UnboundedRepetitions = -1
+
+// RepeatingInterval is an ISO 8601 repeating interval such as
+// R5/2019-05-20T00:00Z/PT1H (five consecutive one-hour intervals) or
+// R/P1D/2019-05-21T00:00:00Z (unbounded daily intervals counting back from the
+// end).
+type RepeatingInterval struct {
+	// Number of intervals, or UnboundedRepetitions
+	Repetitions int
+	Interval    Interval
+}
+
+// String formats the repeating interval in ISO 8601 form.
+func (r RepeatingInterval) String() string {
+	if r.Repetitions == UnboundedRepetitions {
+		return "R/" + r.Interval.String()
+	}
+	return "R" + strconv.Itoa(r.Repetitions) + "/" + r.Interval.String()
+}
+
+// ParseRepeatingInterval parses an ISO 8601 repeating interval of the form
+// Rn/interval, or R/interval for an unbounded one.
+func ParseRepeatingInterval(str string) (r RepeatingInterval, err error) {
+	slash := strings.IndexByte(str, '/')
+	if slash < 1 || (str[0] != 'R' && str[0] != 'r') {
+		return r, fmt.Errorf("smalltime: cannot parse repeating interval %q: expected Rn/", str)
+	}
+	if count := str[1:slash]; count == "" {
+		r.Repetitions = UnboundedRepetitions
+	} else {
+		if count[0] < '0' || count[0] > '9' {
+			return r, fmt.Errorf("smalltime: cannot parse repeating interval %q: invalid repetition count", str)
+		}
+		if r.Repetitions, err = strconv.Atoi(count); err != nil {
+			return r, fmt.Errorf("smalltime: cannot parse repeating interval %q: invalid repetition count", str)
+		}
+	}
+	r.Interval, err = ParseInterval(str[slash+1:])
+	return r, err
+}
+
+// Iterator returns an iterator over the repetitions. Intervals with a start
+// are produced forwards from the start, and duration/end intervals backwards
+// from the end. Each interval is computed from the anchor (rather than from
+// the previous interval), so month arithmetic doesn't drift: repeating P1M
+// from Jan 31 gives Feb 28, Mar 31, Apr 30 and so on.
+func (r RepeatingInterval) Iterator() *IntervalIterator {
+	it := &IntervalIterator{remaining: r.Repetitions}
+	i := r.Interval
+	switch {
+	case !i.Start.IsZero() && !i.End.IsZero():
+		it.anchor, it.step = i.Start, Between(i.Start, i.End)
+	case !i.Start.IsZero():
+		it.anchor, it.step = i.Start, i.Duration
+	case !i.End.IsZero():
+		it.anchor, it.step = i.End, i.Duration.Negate()
+		it.backwards = true
+	default:
+		it.err = ErrUnanchoredInterval
+	}
+	if it.err == nil && it.step.IsZero() {
+		it.err = fmt.Errorf("smalltime: repeating interval %v has a zero duration", r)
+	}
+	return it
+}
+
+// IntervalIterator produces the intervals of a RepeatingInterval.
+type IntervalIterator struct {
+	anchor    Smalltime
+	step      Period
+	backwards bool
+	index     int
+	remaining int
+	err       error
+}
+
+// Next returns the start and end of the next interval, or io.EOF once all
+// repetitions have been produced.
+func (it *IntervalIterator) Next() (start, end Smalltime, err error) {
+	if it.err != nil {
+		return 0, 0, it.err
+	}
+	if it.remaining == 0 {
+		return 0, 0, io.EOF
+	}
+
+	near, err := it.anchor.AddPeriod(it.step.scale(it.index))
+	if err == nil {
+		var far Smalltime
+		if far, err = it.anchor.AddPeriod(it.step.scale(it.index + 1)); err == nil {
+			start, end = near, far
+			if it.backwards {
+				start, end = far, near
+			}
+		}
+	}
+	if err != nil {
+		it.err = err
+		return 0, 0, err
+	}
+
+	it.index++
+	if it.remaining > 0 {
+		it.remaining--
+	}
+	return start, end, nil
+}
+
+func (p Period) scale(factor int) Period {
+	return Period{
+		Years:       p.Years * factor,
+		Months:      p.Months * factor,
+		Days:        p.Days * factor,
+		Hours:       p.Hours * factor,
+		Minutes:     p.Minutes * factor,
+		Seconds:     p.Seconds * factor,
+		Nanoseconds: p.Nanoseconds * factor,
+	}
+}
@@ -0,0 +1,141 @@
+package smalltime
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func assertParseInterval(t *testing.T, str string, expected Interval) {
+	actual, err := ParseInterval(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %v to parse as %+v but got %+v", str, expected, actual)
+	}
+}
+
+func assertIntervals(t *testing.T, str string, expected [][2]Smalltime) {
+	r, err := ParseRepeatingInterval(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", str, err)
+		return
+	}
+	it := r.Iterator()
+	for _, pair := range expected {
+		start, end, err := it.Next()
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", str, err)
+			return
+		}
+		if start != pair[0] || end != pair[1] {
+			t.Errorf("%v: expected %v/%v but got %v/%v", str, Format(pair[0]), Format(pair[1]), Format(start), Format(end))
+		}
+	}
+	if r.Repetitions != UnboundedRepetitions {
+		if _, _, err := it.Next(); err != io.EOF {
+			t.Errorf("%v: expected io.EOF after %v intervals but got %v", str, len(expected), err)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	may20 := NewSmalltime(2019, 5, 20, 0, 0, 0, 0)
+	may21 := NewSmalltime(2019, 5, 21, 0, 0, 0, 0)
+	oneDay := Period{Days: 1}
+
+	assertParseInterval(t, "2019-05-20T00:00:00Z/2019-05-21T00:00:00Z", Interval{Start: may20, End: may21})
+	assertParseInterval(t, "2019-05-20T00:00:00Z/P1D", Interval{Start: may20, Duration: oneDay})
+	assertParseInterval(t, "P1D/2019-05-21T00:00:00Z", Interval{End: may21, Duration: oneDay})
+	assertParseInterval(t, "P1D", Interval{Duration: oneDay})
+	assertParseInterval(t, "2019-05-20T02:00+02:00/PT1H", Interval{Start: may20, Duration: Period{Hours: 1}})
+
+	for _, str := range []string{"", "2019-05-20T00:00:00Z", "P1D/P2D", "2019-05-21/2019-05-20", "a/b/c",
+		"2019-05-20/PX", "P1D/2019-13-01"} {
+		if _, err := ParseInterval(str); err == nil {
+			t.Errorf("Expected parsing %q to fail", str)
+		}
+	}
+}
+
+func TestIntervalStringAndBounds(t *testing.T) {
+	for _, str := range []string{
+		"2019-05-20T00:00:00Z/2019-05-21T00:00:00Z",
+		"2019-05-20T00:00:00Z/P1D",
+		"P1D/2019-05-21T00:00:00Z",
+	} {
+		i, err := ParseInterval(str)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if i.String() != str {
+			t.Errorf("Expected %v to format as itself but got %v", str, i)
+		}
+		start, end, err := i.Bounds()
+		if err != nil || start != NewSmalltime(2019, 5, 20, 0, 0, 0, 0) || end != NewSmalltime(2019, 5, 21, 0, 0, 0, 0) {
+			t.Errorf("%v: unexpected bounds %v/%v (error %v)", str, Format(start), Format(end), err)
+		}
+	}
+
+	i := Interval{Duration: Period{Hours: 1}}
+	if i.String() != "PT1H" {
+		t.Errorf("Expected PT1H but got %v", i)
+	}
+	if _, _, err := i.Bounds(); !errors.Is(err, ErrUnanchoredInterval) {
+		t.Errorf("Expected ErrUnanchoredInterval but got %v", err)
+	}
+}
+
+func TestRepeatingInterval(t *testing.T) {
+	hour := func(h int) Smalltime { return NewSmalltime(2019, 5, 20, h, 0, 0, 0) }
+	assertIntervals(t, "R5/2019-05-20T00:00Z/PT1H", [][2]Smalltime{
+		{hour(0), hour(1)}, {hour(1), hour(2)}, {hour(2), hour(3)}, {hour(3), hour(4)}, {hour(4), hour(5)},
+	})
+	assertIntervals(t, "R2/2019-05-20T00:00:00Z/2019-05-20T02:00:00Z", [][2]Smalltime{
+		{hour(0), hour(2)}, {hour(2), hour(4)},
+	})
+	assertIntervals(t, "R3/PT1H/2019-05-20T10:00:00Z", [][2]Smalltime{
+		{hour(9), hour(10)}, {hour(8), hour(9)}, {hour(7), hour(8)},
+	})
+	assertIntervals(t, "R0/2019-05-20T00:00Z/PT1H", nil)
+
+	month := func(m, d int) Smalltime { return NewSmalltime(2019, m, d, 0, 0, 0, 0) }
+	assertIntervals(t, "R/2019-01-31T00:00:00Z/P1M", [][2]Smalltime{
+		{month(1, 31), month(2, 28)}, {month(2, 28), month(3, 31)}, {month(3, 31), month(4, 30)}, {month(4, 30), month(5, 31)},
+	})
+
+	for _, str := range []string{"R5/2019-05-20T00:00:00Z/PT1H", "R/P1D/2019-05-21T00:00:00Z"} {
+		r, err := ParseRepeatingInterval(str)
+		if err != nil || r.String() != str {
+			t.Errorf("Expected %v to format as itself but got %v (error %v)", str, r, err)
+		}
+	}
+	r, _ := ParseRepeatingInterval("R/P1D/2019-05-21T00:00:00Z")
+	if r.Repetitions != UnboundedRepetitions {
+		t.Errorf("Expected unbounded repetitions but got %v", r.Repetitions)
+	}
+
+	for _, str := range []string{"", "R5", "5/P1D", "R-1/2019-05-20/P1D", "Rx/2019-05-20/P1D", "R5/P1D/P1D"} {
+		if _, err := ParseRepeatingInterval(str); err == nil {
+			t.Errorf("Expected parsing %q to fail", str)
+		}
+	}
+}
+
+func TestIntervalIteratorErrors(t *testing.T) {
+	r, _ := ParseRepeatingInterval("R2/PT1H")
+	if _, _, err := r.Iterator().Next(); !errors.Is(err, ErrUnanchoredInterval) {
+		t.Errorf("Expected ErrUnanchoredInterval but got %v", err)
+	}
+	r, _ = ParseRepeatingInterval("R2/2019-05-20T00:00:00Z/PT0S")
+	if _, _, err := r.Iterator().Next(); err == nil {
+		t.Errorf("Expected a zero duration to fail")
+	}
+	it := RepeatingInterval{UnboundedRepetitions, Interval{Start: MaxSmalltime, Duration: Period{Years: 1}}}.Iterator()
+	if _, _, err := it.Next(); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange but got %v", err)
+	}
+}
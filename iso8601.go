@@ -0,0 +1,229 @@
+package smalltime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fields of an ISO 8601 date & time, before conversion to UTC.
+type isoFields struct {
+	year, month, day            int
+	hour, minute, second, nanos int
+	offsetMinutes               int
+}
+
+type isoParser struct {
+	str      string
+	position int
+}
+
+func (p *isoParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("smalltime: cannot parse %q at offset %v: %v", p.str, p.position, fmt.Sprintf(format, args...))
+}
+
+func (p *isoParser) peek() byte {
+	if p.position >= len(p.str) {
+		return 0
+	}
+	return p.str[p.position]
+}
+
+func (p *isoParser) accept(ch byte) bool {
+	if p.peek() == ch {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *isoParser) expect(ch byte) error {
+	if !p.accept(ch) {
+		return p.errorf("expected '%c'", ch)
+	}
+	return nil
+}
+
+// Reads between minDigits and maxDigits decimal digits.
+func (p *isoParser) digits(minDigits, maxDigits int) (value, count int, err error) {
+	for count < maxDigits {
+		ch := p.peek()
+		if ch < '0' || ch > '9' {
+			break
+		}
+		value = value*10 + int(ch-'0')
+		count++
+		p.position++
+	}
+	if count < minDigits {
+		return 0, count, p.errorf("expected %v digits", minDigits)
+	}
+	return value, count, nil
+}
+
+func (p *isoParser) number(digits int) (int, error) {
+	value, _, err := p.digits(digits, digits)
+	return value, err
+}
+
+// Parses YYYY-MM-DD[(T| )HH:MM[:SS[.fraction]][Z|±HH[[:]MM]]]. The year may
+// have a sign and more than 4 digits, second 60 is accepted as a leap second,
+// and a missing time zone is treated as UTC.
+func parseISO8601(str string) (f isoFields, err error) {
+	p := &isoParser{str: str}
+
+	negative := p.accept('-')
+	if !negative {
+		p.accept('+')
+	}
+	if f.year, _, err = p.digits(4, 9); err != nil {
+		return f, err
+	}
+	if negative {
+		f.year = -f.year
+	}
+	if err = p.expect('-'); err != nil {
+		return f, err
+	}
+	if f.month, err = p.number(2); err != nil {
+		return f, err
+	}
+	if err = p.expect('-'); err != nil {
+		return f, err
+	}
+	if f.day, err = p.number(2); err != nil {
+		return f, err
+	}
+
+	if p.accept('T') || p.accept('t') || p.accept(' ') {
+		if f.hour, err = p.number(2); err != nil {
+			return f, err
+		}
+		if err = p.expect(':'); err != nil {
+			return f, err
+		}
+		if f.minute, err = p.number(2); err != nil {
+			return f, err
+		}
+		if p.accept(':') {
+			if f.second, err = p.number(2); err != nil {
+				return f, err
+			}
+			if p.accept('.') || p.accept(',') {
+				var count int
+				if f.nanos, count, err = p.digits(1, 9); err != nil {
+					return f, err
+				}
+				for ; count < 9; count++ {
+					f.nanos *= 10
+				}
+				// Ignore precision beyond nanoseconds
+				p.digits(0, len(str))
+			}
+		}
+
+		switch ch := p.peek(); {
+		case ch == 'Z' || ch == 'z':
+			p.position++
+		case ch == '+' || ch == '-':
+			p.position++
+			var hours, minutes int
+			if hours, err = p.number(2); err != nil {
+				return f, err
+			}
+			if p.accept(':') || p.peek() != 0 {
+				if minutes, err = p.number(2); err != nil {
+					return f, err
+				}
+				if err = checkRange("offset minute", minutes, 0, 59); err != nil {
+					return f, err
+				}
+			}
+			f.offsetMinutes = hours*60 + minutes
+			if ch == '-' {
+				f.offsetMinutes = -f.offsetMinutes
+			}
+		}
+	}
+
+	if p.position != len(str) {
+		return f, p.errorf("unexpected trailing characters")
+	}
+	return f, f.validate()
+}
+
+func (f *isoFields) validate() error {
+	checks := []error{
+		checkRange("month", f.month, 1, 12),
+		checkRange("hour", f.hour, 0, 23),
+		checkRange("minute", f.minute, 0, 59),
+		checkRange("second", f.second, 0, 60),
+		checkRange("offset minutes", f.offsetMinutes, -24*60+1, 24*60-1),
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	return checkRange("day", f.day, 1, daysInMonth(f.year, f.month))
+}
+
+// Converts the fields to UTC, preserving a leap second.
+func (f *isoFields) toUTC() {
+	if f.offsetMinutes == 0 {
+		return
+	}
+	leapSecond := f.second == 60
+	if leapSecond {
+		f.second = 59
+	}
+	t := time.Date(f.year, time.Month(f.month), f.day, f.hour, f.minute-f.offsetMinutes, f.second, 0, time.UTC)
+	f.year, f.day, f.hour, f.minute, f.second = t.Year(), t.Day(), t.Hour(), t.Minute(), t.Second()
+	f.month = int(t.Month())
+	f.offsetMinutes = 0
+	if leapSecond {
+		f.second = 60
+	}
+}
+
+// ParseSmalltime parses an ISO 8601 date & time (such as
+// "1999-02-15T12:08:45.009122Z" or "2016-12-31T18:59:60-05:00"), converting it
+// to UTC. Sub-microsecond precision is truncated. "infinity", "+infinity" and
+// "-infinity" (in any case) give the infinity sentinels.
+func ParseSmalltime(str string) (Smalltime, error) {
+	switch parseInfinity(str) {
+	case 1:
+		return PositiveInfinitySmalltime, nil
+	case -1:
+		return NegativeInfinitySmalltime, nil
+	}
+	f, err := parseISO8601(str)
+	if err != nil {
+		return 0, err
+	}
+	f.toUTC()
+	if err = checkRange("year", f.year, minYearSmalltime, maxYearSmalltime); err != nil {
+		return 0, err
+	}
+	return NewSmalltime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.nanos/1000), nil
+}
+
+// ParseNanotime parses an ISO 8601 date & time (such as
+// "1999-02-15T12:08:45.010159122Z"), converting it to UTC. "infinity",
+// "+infinity" and "-infinity" (in any case) give the infinity sentinels.
+func ParseNanotime(str string) (Nanotime, error) {
+	switch parseInfinity(str) {
+	case 1:
+		return PositiveInfinityNanotime, nil
+	case -1:
+		return NegativeInfinityNanotime, nil
+	}
+	f, err := parseISO8601(str)
+	if err != nil {
+		return 0, err
+	}
+	f.toUTC()
+	if err = checkRange("year", f.year, minYearNanotime, maxYearNanotime); err != nil {
+		return 0, err
+	}
+	return NewNanotime(f.year, f.month, f.day, f.hour, f.minute, f.second, f.nanos), nil
+}
@@ -0,0 +1,111 @@
+package smalltime
+
+import "testing"
+
+func assertParseSmalltime(t *testing.T, str string, expected Smalltime) {
+	actual, err := ParseSmalltime(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %v to parse as %v but got %v", str, Format(expected), Format(actual))
+	}
+}
+
+func assertParseNanotime(t *testing.T, str string, expected Nanotime) {
+	actual, err := ParseNanotime(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %v to parse as %v but got %v", str, Format(expected), Format(actual))
+	}
+}
+
+func assertParseFails(t *testing.T, str string) {
+	if _, err := ParseSmalltime(str); err == nil {
+		t.Errorf("Expected parsing %v to fail", str)
+	}
+}
+
+func TestParseSmalltime(t *testing.T) {
+	assertParseSmalltime(t, "1999-02-15T12:08:45.009122Z", NewSmalltime(1999, 2, 15, 12, 8, 45, 9122))
+	assertParseSmalltime(t, "1999-02-15 12:08:45.0091229Z", NewSmalltime(1999, 2, 15, 12, 8, 45, 9122))
+	assertParseSmalltime(t, "1999-02-15t12:08:45,5z", NewSmalltime(1999, 2, 15, 12, 8, 45, 500000))
+	assertParseSmalltime(t, "1999-02-15T12:08", NewSmalltime(1999, 2, 15, 12, 8, 0, 0))
+	assertParseSmalltime(t, "1999-02-15", NewSmalltime(1999, 2, 15, 0, 0, 0, 0))
+	assertParseSmalltime(t, "2000-01-01T01:30:00+02:00", NewSmalltime(1999, 12, 31, 23, 30, 0, 0))
+	assertParseSmalltime(t, "1999-12-31T19:30:00-0430", NewSmalltime(2000, 1, 1, 0, 0, 0, 0))
+	assertParseSmalltime(t, "1999-12-31T23:00:00-01", NewSmalltime(2000, 1, 1, 0, 0, 0, 0))
+	assertParseSmalltime(t, "2016-12-31T18:59:60-05:00", NewSmalltime(2016, 12, 31, 23, 59, 60, 0))
+	assertParseSmalltime(t, "-0044-03-15T12:00:00Z", NewSmalltime(-44, 3, 15, 12, 0, 0, 0))
+	assertParseSmalltime(t, "+120000-06-01T00:00:00Z", NewSmalltime(120000, 6, 1, 0, 0, 0, 0))
+	assertParseSmalltime(t, "+131071-12-31T23:59:59.999999Z", MaxSmalltime)
+	assertParseSmalltime(t, "-131072-01-01T00:00:00Z", MinSmalltime)
+	assertParseSmalltime(t, "-0001-12-31T23:59:59Z", NewSmalltime(-1, 12, 31, 23, 59, 59, 0))
+	assertParseSmalltime(t, "0000-02-29", NewSmalltime(0, 2, 29, 0, 0, 0, 0))
+}
+
+func TestParseNanotime(t *testing.T) {
+	assertParseNanotime(t, "1999-02-15T12:08:45.010159122Z", NewNanotime(1999, 2, 15, 12, 8, 45, 10159122))
+	assertParseNanotime(t, "1970-01-01T00:00:00Z", NewNanotime(1970, 1, 1, 0, 0, 0, 0))
+	assertParseNanotime(t, "2225-12-31T23:59:59.999999999Z", NewNanotime(2225, 12, 31, 23, 59, 59, 999999999))
+
+	for _, str := range []string{"1969-12-31T23:59:59Z", "2226-01-01T00:00:00Z", "1970-01-01T00:30:00+01:00"} {
+		if _, err := ParseNanotime(str); err == nil {
+			t.Errorf("Expected parsing %v to fail", str)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	assertParseFails(t, "")
+	assertParseFails(t, "1999")
+	assertParseFails(t, "99-02-15")
+	assertParseFails(t, "1999-2-15")
+	assertParseFails(t, "1999-02-15T")
+	assertParseFails(t, "1999-02-15T12")
+	assertParseFails(t, "1999-02-15T12:08:45.")
+	assertParseFails(t, "1999-02-15T12:08:45Zx")
+	assertParseFails(t, "1999-02-29")
+	assertParseFails(t, "-0100-02-29")
+	assertParseFails(t, "-131073-01-01")
+	assertParseFails(t, "+131072-01-01")
+	assertParseFails(t, "1999-13-01")
+	assertParseFails(t, "1999-02-15T24:00:00Z")
+	assertParseFails(t, "1999-02-15T12:60:00Z")
+	assertParseFails(t, "1999-02-15T12:00:61Z")
+	assertParseFails(t, "1999-02-15T12:00:00+24:00")
+	assertParseFails(t, "1999-02-15T12:00:00+05:99")
+	assertParseFails(t, "1999-02-15T12:00:00-0560")
+	assertParseFails(t, "200000-01-01")
+}
+
+func TestValidate(t *testing.T) {
+	if err := NewSmalltime(2000, 2, 29, 23, 59, 60, 999999).Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := NewNanotime(2000, 2, 29, 23, 59, 60, 999999999).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	invalidSmalltimes := []Smalltime{
+		NewSmalltime(2001, 2, 29, 0, 0, 0, 0),
+		NewSmalltime(2000, 0, 1, 0, 0, 0, 0),
+		NewSmalltime(2000, 1, 0, 0, 0, 0, 0),
+		NewSmalltime(2000, 1, 1, 24, 0, 0, 0),
+		NewSmalltime(2000, 1, 1, 0, 60, 0, 0),
+		NewSmalltime(2000, 1, 1, 0, 0, 61, 0),
+		NewSmalltime(2000, 1, 1, 0, 0, 0, 1000000),
+	}
+	for _, value := range invalidSmalltimes {
+		if err := value.Validate(); err == nil {
+			t.Errorf("Expected 0x%016x to be invalid", uint64(value))
+		}
+	}
+	if err := NewNanotime(2000, 1, 1, 0, 0, 0, 1000000000).Validate(); err == nil {
+		t.Errorf("Expected nanosecond 1000000000 to be invalid")
+	}
+}
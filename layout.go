@@ -0,0 +1,197 @@
+package smalltime
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Field describes where a date or time field is stored in an encoded value.
+type Field struct {
+	Name string
+	// Position of the field's least significant bit
+	Shift uint
+	// Number of bits in the field
+	Width uint
+	// Added to the stored bits to get the field value (Nanotime stores year-1970)
+	Bias int
+	// Whether the stored bits are two's complement
+	Signed bool
+	// Valid range of the field value. The maximum day depends on the month and
+	// year, so Max for the day field is 31.
+	Min, Max int
+}
+
+// Mask returns the bits occupied by the field.
+func (f Field) Mask() uint64 {
+	return (1<<f.Width - 1) << f.Shift
+}
+
+// Bits returns the stored bits of the field, shifted down to bit 0.
+func (f Field) Bits(raw uint64) uint64 {
+	return raw & f.Mask() >> f.Shift
+}
+
+// Value returns the field value stored in an encoded value.
+func (f Field) Value(raw uint64) int {
+	value := int(f.Bits(raw))
+	if f.Signed && value >= 1<<(f.Width-1) {
+		value -= 1 << f.Width
+	}
+	return value + f.Bias
+}
+
+// Layout describes the fields of an encoding, from most to least significant.
+type Layout struct {
+	Name   string
+	Fields []Field
+}
+
+func fieldFromMask(name string, mask uint64, shift uint, min, max int) Field {
+	return Field{
+		Name:  name,
+		Shift: shift,
+		Width: uint(bits.OnesCount64(mask)),
+		Min:   min,
+		Max:   max,
+	}
+}
+
+var smalltimeLayout = Layout{
+	Name: "smalltime",
+	Fields: []Field{
+		{Name: "year", Shift: bitshiftYear, Width: uint(bits.OnesCount64(maskYear)), Signed: true, Min: minYearSmalltime, Max: maxYearSmalltime},
+		fieldFromMask("month", uint64(maskMonth), bitshiftMonth, 1, 12),
+		fieldFromMask("day", uint64(maskDay), bitshiftDay, 1, 31),
+		fieldFromMask("hour", uint64(maskHour), bitshiftHour, 0, 23),
+		fieldFromMask("minute", uint64(maskMinute), bitshiftMinute, 0, 59),
+		fieldFromMask("second", uint64(maskSecond), bitshiftSecond, 0, 60),
+		fieldFromMask("microsecond", uint64(maskMicrosecond), 0, 0, 999999),
+	},
+}
+
+var nanotimeLayout = Layout{
+	Name: "nanotime",
+	Fields: []Field{
+		{Name: "year", Shift: bitshiftYearNanotime, Width: uint(bits.OnesCount64(uint64(maskYearNanotime))),
+			Bias: zeroYearNanotime, Min: minYearNanotime, Max: maxYearNanotime},
+		fieldFromMask("month", uint64(maskMonthNanotime), bitshiftMonthNanotime, 1, 12),
+		fieldFromMask("day", uint64(maskDayNanotime), bitshiftDayNanotime, 1, 31),
+		fieldFromMask("hour", uint64(maskHourNanotime), bitshiftHourNanotime, 0, 23),
+		fieldFromMask("minute", uint64(maskMinuteNanotime), bitshiftMinuteNanotime, 0, 59),
+		fieldFromMask("second", uint64(maskSecondNanotime), bitshiftSecondNanotime, 0, 60),
+		fieldFromMask("nanosecond", uint64(maskNanoNanotime), 0, 0, 999999999),
+	},
+}
+
+// SmalltimeLayout describes the bit layout of Smalltime. The result is a copy,
+// and can be modified freely.
+func SmalltimeLayout() Layout {
+	return smalltimeLayout.clone()
+}
+
+// NanotimeLayout describes the bit layout of Nanotime. The result is a copy,
+// and can be modified freely.
+func NanotimeLayout() Layout {
+	return nanotimeLayout.clone()
+}
+
+func (l Layout) clone() Layout {
+	l.Fields = append([]Field(nil), l.Fields...)
+	return l
+}
+
+// Returns the valid range of each field in an encoded value, narrowing the
+// day's maximum to the length of the encoded month.
+func (l Layout) limits(raw uint64) (min, max []int) {
+	year, month := 0, 0
+	for _, f := range l.Fields {
+		min = append(min, f.Min)
+		max = append(max, f.Max)
+		switch f.Name {
+		case "year":
+			year = f.Value(raw)
+		case "month":
+			month = f.Value(raw)
+		}
+	}
+	if month >= 1 && month <= 12 {
+		for i, f := range l.Fields {
+			if f.Name == "day" {
+				max[i] = daysInMonth(year, month)
+			}
+		}
+	}
+	return
+}
+
+// Dump renders a bit-level breakdown of an encoded value, one line per field.
+// Fields whose value is out of range are flagged, and marked with ^ under the
+// bit string.
+//
+//	smalltime 0x01f3c9ec22d023a2
+//	000000011111001111 0010 01111 01100 001000 101101 00000010001110100010
+//	year         bits 63-46  000000011111001111    1999
+//	...
+func (l Layout) Dump(raw uint64) string {
+	min, max := l.limits(raw)
+
+	var sb, bitRow, markRow strings.Builder
+	var fieldRows []string
+	hasInvalid := false
+	for i, f := range l.Fields {
+		fieldBits := fmt.Sprintf("%0*b", f.Width, f.Bits(raw))
+		value := f.Value(raw)
+		row := fmt.Sprintf("%-12v bits %2d-%-2d  %-*v  %v",
+			f.Name, f.Shift+f.Width-1, f.Shift, l.maxWidth(), fieldBits, value)
+		mark := " "
+		if value < min[i] || value > max[i] {
+			row += fmt.Sprintf("  <-- out of range [%v, %v]", min[i], max[i])
+			mark = "^"
+			hasInvalid = true
+		}
+		fieldRows = append(fieldRows, row)
+
+		if i > 0 {
+			bitRow.WriteByte(' ')
+			markRow.WriteByte(' ')
+		}
+		bitRow.WriteString(fieldBits)
+		markRow.WriteString(strings.Repeat(mark, int(f.Width)))
+	}
+
+	fmt.Fprintf(&sb, "%v 0x%016x\n", l.Name, raw)
+	sb.WriteString(bitRow.String())
+	sb.WriteByte('\n')
+	if hasInvalid {
+		sb.WriteString(strings.TrimRight(markRow.String(), " "))
+		sb.WriteByte('\n')
+	}
+	for _, row := range fieldRows {
+		sb.WriteString(strings.TrimRight(row, " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func (l Layout) maxWidth() int {
+	width := 0
+	for _, f := range l.Fields {
+		if int(f.Width) > width {
+			width = int(f.Width)
+		}
+	}
+	return width
+}
+
+// Dump renders a bit-level breakdown of the value, flagging out-of-range
+// fields. See Layout.Dump.
+func (time Smalltime) Dump() string {
+	return smalltimeLayout.Dump(uint64(time))
+}
+
+// Dump renders a bit-level breakdown of the value, flagging out-of-range
+// fields. See Layout.Dump.
+func (time Nanotime) Dump() string {
+	return nanotimeLayout.Dump(uint64(time))
+}
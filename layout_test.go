@@ -0,0 +1,85 @@
+package smalltime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutMatchesAccessors(t *testing.T) {
+	s := NewSmalltime(-1999, 2, 15, 12, 8, 45, 9122)
+	expected := []int{s.Year(), s.Month(), s.Day(), s.Hour(), s.Minute(), s.Second(), s.Microsecond()}
+	for i, f := range SmalltimeLayout().Fields {
+		if actual := f.Value(uint64(s)); actual != expected[i] {
+			t.Errorf("Expected %v to be %v but got %v", f.Name, expected[i], actual)
+		}
+	}
+
+	n := NewNanotime(2100, 2, 15, 12, 8, 45, 10159122)
+	expected = []int{n.Year(), n.Month(), n.Day(), n.Hour(), n.Minute(), n.Second(), n.Nanosecond()}
+	for i, f := range NanotimeLayout().Fields {
+		if actual := f.Value(uint64(n)); actual != expected[i] {
+			t.Errorf("Expected %v to be %v but got %v", f.Name, expected[i], actual)
+		}
+	}
+}
+
+func TestLayoutCoversAllBits(t *testing.T) {
+	for _, layout := range []Layout{SmalltimeLayout(), NanotimeLayout()} {
+		var covered uint64
+		for _, f := range layout.Fields {
+			if covered&f.Mask() != 0 {
+				t.Errorf("%v: field %v overlaps another field", layout.Name, f.Name)
+			}
+			covered |= f.Mask()
+		}
+		if covered != ^uint64(0) {
+			t.Errorf("%v: fields cover 0x%016x instead of all bits", layout.Name, covered)
+		}
+	}
+}
+
+func TestLayoutIsACopy(t *testing.T) {
+	layout := SmalltimeLayout()
+	layout.Fields[0].Max = 0
+	if year := SmalltimeLayout().Fields[0]; year.Min != MinSmalltime.Year() || year.Max != MaxSmalltime.Year() {
+		t.Errorf("Expected the year range [%v, %v] but got [%v, %v]", MinSmalltime.Year(), MaxSmalltime.Year(), year.Min, year.Max)
+	}
+	if year := NanotimeLayout().Fields[0]; year.Min != MinNanotime.Year() || year.Max != MaxNanotime.Year() {
+		t.Errorf("Expected the year range [%v, %v] but got [%v, %v]", MinNanotime.Year(), MaxNanotime.Year(), year.Min, year.Max)
+	}
+}
+
+func TestDump(t *testing.T) {
+	expected := `smalltime 0x01f3c9ec22d023a2
+000000011111001111 0010 01111 01100 001000 101101 00000010001110100010
+year         bits 63-46  000000011111001111    1999
+month        bits 45-42  0010                  2
+day          bits 41-37  01111                 15
+hour         bits 36-32  01100                 12
+minute       bits 31-26  001000                8
+second       bits 25-20  101101                45
+microsecond  bits 19-0   00000010001110100010  9122
+`
+	if actual := NewSmalltime(1999, 2, 15, 12, 8, 45, 9122).Dump(); actual != expected {
+		t.Errorf("Expected\n%v\nbut got\n%v", expected, actual)
+	}
+}
+
+func TestDumpFlagsInvalidFields(t *testing.T) {
+	dump := NewSmalltime(-1, 2, 29, 24, 8, 45, 9122).Dump()
+	lines := strings.Split(dump, "\n")
+	if lines[2] != "                        ^^^^^ ^^^^^" {
+		t.Errorf("Unexpected marker row %q", lines[2])
+	}
+	if !strings.Contains(dump, "29  <-- out of range [1, 28]") || !strings.Contains(dump, "24  <-- out of range [0, 23]") {
+		t.Errorf("Expected day and hour to be flagged:\n%v", dump)
+	}
+
+	dump = Nanotime(NewNanotime(2000, 1, 1, 0, 0, 0, 0) | maskNanoNanotime).Dump()
+	if !strings.Contains(dump, "1073741823  <-- out of range [0, 999999999]") {
+		t.Errorf("Expected nanosecond to be flagged:\n%v", dump)
+	}
+	if strings.Count(dump, "<--") != 1 {
+		t.Errorf("Expected only one flagged field:\n%v", dump)
+	}
+}
@@ -0,0 +1,293 @@
+package smalltime
+
+import "strings"
+
+// NameWidth selects the form of a month, weekday or day period name.
+type NameWidth int
+
+const (
+	// Abbreviated names, such as "Jan" or "Mon".
+	NameAbbreviated NameWidth = iota
+	// Full names, such as "January" or "Monday".
+	NameWide
+	// Single letter names, such as "J" or "M". These are ambiguous, so they
+	// are only for display.
+	NameNarrow
+	nameWidthCount
+)
+
+// DateStyle selects one of a locale's default date patterns.
+type DateStyle int
+
+const (
+	// Numeric, such as 5/20/19.
+	DateShort DateStyle = iota
+	// Abbreviated month name, such as May 20, 2019.
+	DateMedium
+	// Full month name, such as May 20, 2019.
+	DateLong
+	// Full month and weekday names, such as Monday, May 20, 2019.
+	DateFull
+	dateStyleCount
+)
+
+// Locale supplies the localized names and date patterns used by FormatLocale
+// and the Parse...Locale functions.
+type Locale interface {
+	// MonthName returns the name of month (1-12).
+	MonthName(month int, width NameWidth) string
+	// WeekdayName returns the name of weekday (0-6, from Sunday).
+	WeekdayName(weekday int, width NameWidth) string
+	// DayPeriodName returns the name for the morning (AM) or afternoon (PM).
+	DayPeriodName(pm bool) string
+	// DatePattern returns a strftime layout for the style.
+	DatePattern(style DateStyle) string
+}
+
+// LocaleTable is a Locale backed by fixed tables, indexed by NameWidth and
+// DateStyle.
+type LocaleTable struct {
+	// BCP 47 language tag, such as "en" or "de".
+	Tag          string
+	Months       [nameWidthCount][12]string
+	Weekdays     [nameWidthCount][7]string
+	DayPeriods   [2]string
+	DatePatterns [dateStyleCount]string
+}
+
+func (l *LocaleTable) MonthName(month int, width NameWidth) string {
+	if month < 1 || month > 12 || width < 0 || width >= nameWidthCount {
+		return "?"
+	}
+	return l.Months[width][month-1]
+}
+
+func (l *LocaleTable) WeekdayName(weekday int, width NameWidth) string {
+	if weekday < 0 || weekday > 6 || width < 0 || width >= nameWidthCount {
+		return "?"
+	}
+	return l.Weekdays[width][weekday]
+}
+
+func (l *LocaleTable) DayPeriodName(pm bool) string {
+	if pm {
+		return l.DayPeriods[1]
+	}
+	return l.DayPeriods[0]
+}
+
+func (l *LocaleTable) DatePattern(style DateStyle) string {
+	if style < 0 || style >= dateStyleCount {
+		style = DateMedium
+	}
+	return l.DatePatterns[style]
+}
+
+func monthNames(locale Locale, width NameWidth) []string {
+	names := make([]string, 12)
+	for i := range names {
+		names[i] = locale.MonthName(i+1, width)
+	}
+	return names
+}
+
+func weekdayNames(locale Locale, width NameWidth) []string {
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = locale.WeekdayName(i, width)
+	}
+	return names
+}
+
+// LocaleForTag returns a copy of the built-in locale for a BCP 47 language tag,
+// matching on the primary language only ("de-AT" and "de_CH" give
+// GermanLocale).
+func LocaleForTag(tag string) (Locale, bool) {
+	language := strings.ToLower(tag)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	for _, locale := range builtinLocales {
+		if locale.Tag == language {
+			return locale.copy(), true
+		}
+	}
+	return nil, false
+}
+
+var builtinLocales = []*LocaleTable{englishLocale, germanLocale, frenchLocale,
+	spanishLocale, italianLocale, dutchLocale, portugueseLocale}
+
+// The built-in locales return copies of their tables, which can be modified
+// to make new locales without affecting the originals. EnglishLocale is the
+// default for Format and the Parse...Layout functions.
+func EnglishLocale() *LocaleTable    { return englishLocale.copy() }
+func GermanLocale() *LocaleTable     { return germanLocale.copy() }
+func FrenchLocale() *LocaleTable     { return frenchLocale.copy() }
+func SpanishLocale() *LocaleTable    { return spanishLocale.copy() }
+func ItalianLocale() *LocaleTable    { return italianLocale.copy() }
+func DutchLocale() *LocaleTable      { return dutchLocale.copy() }
+func PortugueseLocale() *LocaleTable { return portugueseLocale.copy() }
+
+// The tables hold only arrays and strings, so a shallow copy is a deep one.
+func (l *LocaleTable) copy() *LocaleTable {
+	table := *l
+	return &table
+}
+
+// Taken from the format-context Gregorian calendar data in CLDR.
+var (
+	englishLocale = &LocaleTable{
+		Tag: "en",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			NameWide: {"January", "February", "March", "April", "May", "June",
+				"July", "August", "September", "October", "November", "December"},
+			NameNarrow: {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			NameWide:        {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			NameNarrow:      {"S", "M", "T", "W", "T", "F", "S"},
+		},
+		DayPeriods: [2]string{"AM", "PM"},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%-m/%-d/%y",
+			DateMedium: "%b %-d, %Y",
+			DateLong:   "%B %-d, %Y",
+			DateFull:   "%A, %B %-d, %Y",
+		},
+	}
+
+	germanLocale = &LocaleTable{
+		Tag: "de",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+			NameWide: {"Januar", "Februar", "März", "April", "Mai", "Juni",
+				"Juli", "August", "September", "Oktober", "November", "Dezember"},
+			NameNarrow: {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+			NameWide:        {"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			NameNarrow:      {"S", "M", "D", "M", "D", "F", "S"},
+		},
+		DayPeriods: [2]string{"AM", "PM"},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%d.%m.%y",
+			DateMedium: "%d.%m.%Y",
+			DateLong:   "%-d. %B %Y",
+			DateFull:   "%A, %-d. %B %Y",
+		},
+	}
+
+	frenchLocale = &LocaleTable{
+		Tag: "fr",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			NameWide: {"janvier", "février", "mars", "avril", "mai", "juin",
+				"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			NameNarrow: {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			NameWide:        {"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			NameNarrow:      {"D", "L", "M", "M", "J", "V", "S"},
+		},
+		DayPeriods: [2]string{"AM", "PM"},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%d/%m/%Y",
+			DateMedium: "%-d %b %Y",
+			DateLong:   "%-d %B %Y",
+			DateFull:   "%A %-d %B %Y",
+		},
+	}
+
+	spanishLocale = &LocaleTable{
+		Tag: "es",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			NameWide: {"enero", "febrero", "marzo", "abril", "mayo", "junio",
+				"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			NameNarrow: {"E", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			NameWide:        {"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			NameNarrow:      {"D", "L", "M", "X", "J", "V", "S"},
+		},
+		DayPeriods: [2]string{"a.\u00a0m.", "p.\u00a0m."},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%-d/%-m/%y",
+			DateMedium: "%-d %b %Y",
+			DateLong:   "%-d de %B de %Y",
+			DateFull:   "%A, %-d de %B de %Y",
+		},
+	}
+
+	italianLocale = &LocaleTable{
+		Tag: "it",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+			NameWide: {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+				"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			NameNarrow: {"G", "F", "M", "A", "M", "G", "L", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+			NameWide:        {"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+			NameNarrow:      {"D", "L", "M", "M", "G", "V", "S"},
+		},
+		DayPeriods: [2]string{"AM", "PM"},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%d/%m/%y",
+			DateMedium: "%-d %b %Y",
+			DateLong:   "%-d %B %Y",
+			DateFull:   "%A %-d %B %Y",
+		},
+	}
+
+	dutchLocale = &LocaleTable{
+		Tag: "nl",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+			NameWide: {"januari", "februari", "maart", "april", "mei", "juni",
+				"juli", "augustus", "september", "oktober", "november", "december"},
+			NameNarrow: {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"zo", "ma", "di", "wo", "do", "vr", "za"},
+			NameWide:        {"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+			NameNarrow:      {"Z", "M", "D", "W", "D", "V", "Z"},
+		},
+		DayPeriods: [2]string{"a.m.", "p.m."},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%d-%m-%Y",
+			DateMedium: "%-d %b %Y",
+			DateLong:   "%-d %B %Y",
+			DateFull:   "%A %-d %B %Y",
+		},
+	}
+
+	portugueseLocale = &LocaleTable{
+		Tag: "pt",
+		Months: [nameWidthCount][12]string{
+			NameAbbreviated: {"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+			NameWide: {"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+				"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+			NameNarrow: {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+		},
+		Weekdays: [nameWidthCount][7]string{
+			NameAbbreviated: {"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+			NameWide:        {"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+			NameNarrow:      {"D", "S", "T", "Q", "Q", "S", "S"},
+		},
+		DayPeriods: [2]string{"AM", "PM"},
+		DatePatterns: [dateStyleCount]string{
+			DateShort:  "%d/%m/%Y",
+			DateMedium: "%-d de %b de %Y",
+			DateLong:   "%-d de %B de %Y",
+			DateFull:   "%A, %-d de %B de %Y",
+		},
+	}
+)
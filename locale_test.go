@@ -0,0 +1,132 @@
+package smalltime
+
+import "testing"
+
+func assertLocale(t *testing.T, value Smalltime, layout string, locale Locale, expected string) {
+	if actual := value.FormatLocale(layout, locale); actual != expected {
+		t.Errorf("Expected %v formatted with %q to be %q but got %q", Format(value), layout, expected, actual)
+	}
+	parsed, err := ParseSmalltimeLocale(layout, expected, locale)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %q with %q: %v", expected, layout, err)
+	} else if reformatted := parsed.FormatLocale(layout, locale); reformatted != expected {
+		t.Errorf("Expected %q parsed with %q to format the same but got %q", expected, layout, reformatted)
+	}
+}
+
+func assertDatePatterns(t *testing.T, locale Locale, value Smalltime, expected ...string) {
+	for style := DateShort; style <= DateFull; style++ {
+		assertLocale(t, value, locale.DatePattern(style), locale, expected[style])
+	}
+}
+
+func TestDatePatterns(t *testing.T) {
+	value := NewSmalltime(2019, 3, 4, 15, 4, 5, 0)
+	assertDatePatterns(t, EnglishLocale(), value, "3/4/19", "Mar 4, 2019", "March 4, 2019", "Monday, March 4, 2019")
+	assertDatePatterns(t, GermanLocale(), value, "04.03.19", "04.03.2019", "4. März 2019", "Montag, 4. März 2019")
+	assertDatePatterns(t, FrenchLocale(), value, "04/03/2019", "4 mars 2019", "4 mars 2019", "lundi 4 mars 2019")
+	assertDatePatterns(t, SpanishLocale(), value, "4/3/19", "4 mar 2019", "4 de marzo de 2019", "lunes, 4 de marzo de 2019")
+	assertDatePatterns(t, ItalianLocale(), value, "04/03/19", "4 mar 2019", "4 marzo 2019", "lunedì 4 marzo 2019")
+	assertDatePatterns(t, DutchLocale(), value, "04-03-2019", "4 mrt 2019", "4 maart 2019", "maandag 4 maart 2019")
+	assertDatePatterns(t, PortugueseLocale(), value, "04/03/2019", "4 de mar. de 2019", "4 de março de 2019", "segunda-feira, 4 de março de 2019")
+}
+
+func TestFormatLocale(t *testing.T) {
+	value := NewSmalltime(2019, 9, 1, 15, 4, 5, 0)
+	assertLocale(t, value, "%a %d %b %Y", GermanLocale(), "So. 01 Sept. 2019")
+	assertLocale(t, value, "Monday 2 January 2006", FrenchLocale(), "dimanche 1 septembre 2019")
+	assertLocale(t, value, "Mon, 2 Jan 2006", DutchLocale(), "zo, 1 sep 2019")
+	assertLocale(t, value, "%I:%M %p", SpanishLocale(), "03:04 p.\u00a0m.")
+	assertLocale(t, value, "%I:%M %p", DutchLocale(), "03:04 p.m.")
+	assertLocale(t, NewSmalltime(2019, 6, 1, 0, 0, 0, 0), "%b %Y", GermanLocale(), "Juni 2019")
+	assertLocale(t, NewSmalltime(2019, 7, 1, 0, 0, 0, 0), "%b %Y", FrenchLocale(), "juil. 2019")
+
+	// Names are matched case-insensitively, preferring the longest
+	parsed, err := ParseSmalltimeLocale("%d %B %Y", "01 JUILLET 2019", FrenchLocale())
+	if err != nil || parsed != NewSmalltime(2019, 7, 1, 0, 0, 0, 0) {
+		t.Errorf("Unexpected result %v (error %v)", Format(parsed), err)
+	}
+	if _, err := ParseSmalltimeLocale("%d %B %Y", "01 July 2019", FrenchLocale()); err == nil {
+		t.Errorf("Expected an English name to fail in French")
+	}
+	nano, err := ParseNanotimeLocale("%A %-d %B %Y", "sábado 1 junio 2019", SpanishLocale())
+	if err != nil || nano != NewNanotime(2019, 6, 1, 0, 0, 0, 0) {
+		t.Errorf("Unexpected result %v (error %v)", Format(nano), err)
+	}
+	if actual := NewNanotime(2019, 6, 1, 0, 0, 0, 0).FormatLocale("%A %-d %B", ItalianLocale()); actual != "sabato 1 giugno" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+}
+
+func TestLocaleNames(t *testing.T) {
+	if actual := GermanLocale().MonthName(3, NameNarrow); actual != "M" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	if actual := SpanishLocale().WeekdayName(3, NameNarrow); actual != "X" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	if actual := EnglishLocale().MonthName(13, NameWide); actual != "?" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+	for _, locale := range builtinLocales {
+		for width := NameAbbreviated; width < nameWidthCount; width++ {
+			for i := 0; i < 12; i++ {
+				if locale.Months[width][i] == "" {
+					t.Errorf("%v: missing month %v width %v", locale.Tag, i+1, width)
+				}
+			}
+			for i := 0; i < 7; i++ {
+				if locale.Weekdays[width][i] == "" {
+					t.Errorf("%v: missing weekday %v width %v", locale.Tag, i, width)
+				}
+			}
+		}
+	}
+}
+
+func TestLocaleCopies(t *testing.T) {
+	modified := GermanLocale()
+	modified.Months[NameWide][2] = "Lenzing"
+	found, _ := LocaleForTag("de")
+	found.(*LocaleTable).Weekdays[NameWide][1] = "Mondtag"
+	value := NewSmalltime(2019, 3, 4, 0, 0, 0, 0)
+	if actual := value.FormatLocale("%A %B", modified); actual != "Montag Lenzing" {
+		t.Errorf("Expected Montag Lenzing but got %q", actual)
+	}
+	if actual := value.FormatLocale("%A %B", GermanLocale()); actual != "Montag März" {
+		t.Errorf("Expected the built-in table to be unchanged but got %q", actual)
+	}
+}
+
+func TestNarrowNames(t *testing.T) {
+	value := NewSmalltime(2019, 3, 6, 0, 0, 0, 0)
+	if actual := value.Format("%!a %!b"); actual != "W M" {
+		t.Errorf("Expected W M but got %q", actual)
+	}
+	if actual := value.FormatLocale("%!A %!B", SpanishLocale()); actual != "X M" {
+		t.Errorf("Expected X M but got %q", actual)
+	}
+	if actual := value.Format("%!d %!Y"); actual != "%!d %!Y" {
+		t.Errorf("Expected the narrow flag to be literal on numbers but got %q", actual)
+	}
+	if _, err := ParseSmalltimeLayout("%!b %Y", "M 2019"); err == nil {
+		t.Errorf("Expected narrow names to be rejected when parsing")
+	}
+}
+
+func TestLocaleForTag(t *testing.T) {
+	for tag, expected := range map[string]*LocaleTable{
+		"en":    EnglishLocale(),
+		"de-AT": GermanLocale(),
+		"fr_CA": FrenchLocale(),
+		"ES":    SpanishLocale(),
+		"pt-BR": PortugueseLocale(),
+	} {
+		if actual, ok := LocaleForTag(tag); !ok || *actual.(*LocaleTable) != *expected {
+			t.Errorf("Unexpected locale for %v", tag)
+		}
+	}
+	if _, ok := LocaleForTag("xx"); ok {
+		t.Errorf("Expected an unknown tag to fail")
+	}
+}
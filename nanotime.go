@@ -6,6 +6,9 @@ type Nanotime uint64
 
 const zeroYearNanotime = 1970
 
+const minYearNanotime = zeroYearNanotime
+const maxYearNanotime = zeroYearNanotime + 255
+
 const bitshiftYearNanotime = 56
 const bitshiftMonthNanotime = 52
 const bitshiftDayNanotime = 47
@@ -21,12 +24,42 @@ const maskMinuteNanotime = Nanotime(0x3f) << (bitshiftMinuteNanotime)
 const maskSecondNanotime = Nanotime(0x3f) << (bitshiftSecondNanotime)
 const maskNanoNanotime = Nanotime(0x3fffffff)
 
+// MinNanotime is the earliest representable date & time: 1970-01-01T00:00:00Z
+const MinNanotime = Nanotime(1)<<bitshiftMonthNanotime | 1<<bitshiftDayNanotime
+
+// MaxNanotime is the latest representable date & time: 2225-12-31T23:59:59.999999999Z.
+// It isn't a leap second, so that it still converts to a time.Time in range.
+const MaxNanotime = Nanotime(maxYearNanotime-zeroYearNanotime)<<bitshiftYearNanotime |
+	12<<bitshiftMonthNanotime | 31<<bitshiftDayNanotime | 23<<bitshiftHourNanotime |
+	59<<bitshiftMinuteNanotime | 59<<bitshiftSecondNanotime | 999999999
+
 func NanotimeFromTime(t time.Time) Nanotime {
 	t = t.UTC()
 	return NewNanotime(t.Year(), int(t.Month()), t.Day(), t.Hour(),
 		t.Minute(), t.Second(), t.Nanosecond())
 }
 
+// NanotimeFromTimeChecked converts t, returning a RangeError (matching
+// ErrOutOfRange) if its year is outside of [MinNanotime, MaxNanotime].
+func NanotimeFromTimeChecked(t time.Time) (Nanotime, error) {
+	if err := checkRange("year", t.UTC().Year(), minYearNanotime, maxYearNanotime); err != nil {
+		return 0, err
+	}
+	return NanotimeFromTime(t), nil
+}
+
+// NanotimeFromTimeSaturating converts t, clamping it to
+// [MinNanotime, MaxNanotime].
+func NanotimeFromTimeSaturating(t time.Time) Nanotime {
+	switch year := t.UTC().Year(); {
+	case year < minYearNanotime:
+		return MinNanotime
+	case year > maxYearNanotime:
+		return MaxNanotime
+	}
+	return NanotimeFromTime(t)
+}
+
 func NewNanotime(year, month, day, hour, minute, second, nanosecond int) Nanotime {
 	return Nanotime(year-zeroYearNanotime)<<(bitshiftYearNanotime) |
 		Nanotime(month)<<(bitshiftMonthNanotime) |
@@ -82,3 +115,111 @@ func (time Nanotime) Second() int {
 func (time Nanotime) Nanosecond() int {
 	return int(time & maskNanoNanotime)
 }
+
+// Validate returns a RangeError for the first field that is out of range (for
+// example, a day that doesn't exist in the month), or nil if all are valid.
+func (time Nanotime) Validate() error {
+	return validateFields(time.Year(), time.Month(), time.Day(), time.Hour(), time.Minute(),
+		time.Second(), time.Nanosecond(), 999999999, "nanosecond")
+}
+
+func (time Nanotime) Millisecond() int {
+	return time.Nanosecond() / 1000000
+}
+
+func (time Nanotime) Microsecond() int {
+	return time.Nanosecond() / 1000
+}
+
+func (time Nanotime) SubsecondNanos() int {
+	return time.Nanosecond()
+}
+
+// Fraction returns the sub-second portion truncated to the specified number of
+// decimal digits (0-9), for fixed-point printing.
+func (time Nanotime) Fraction(digits int) int {
+	return fraction(time.SubsecondNanos(), digits)
+}
+
+func (time Nanotime) WithMillisecond(millisecond int) (Nanotime, error) {
+	if err := checkRange("millisecond", millisecond, 0, 999); err != nil {
+		return time, err
+	}
+	return time.withNanosecond(millisecond * 1000000), nil
+}
+
+func (time Nanotime) WithMicrosecond(microsecond int) (Nanotime, error) {
+	if err := checkRange("microsecond", microsecond, 0, 999999); err != nil {
+		return time, err
+	}
+	return time.withNanosecond(microsecond * 1000), nil
+}
+
+func (time Nanotime) WithNanosecond(nanosecond int) (Nanotime, error) {
+	if err := checkRange("nanosecond", nanosecond, 0, 999999999); err != nil {
+		return time, err
+	}
+	return time.withNanosecond(nanosecond), nil
+}
+
+func (time Nanotime) withNanosecond(nanosecond int) Nanotime {
+	return time&^maskNanoNanotime | Nanotime(nanosecond)
+}
+
+func (time Nanotime) WithYear(year int, policy DayPolicy) (Nanotime, error) {
+	if err := checkRange("year", year, minYearNanotime, maxYearNanotime); err != nil {
+		return time, err
+	}
+	day, err := applyDayPolicy(policy, year, time.Month(), time.Day())
+	if err != nil {
+		return time, err
+	}
+	time = time&^maskDayNanotime | Nanotime(day)<<bitshiftDayNanotime
+	return time&^maskYearNanotime | Nanotime(year-zeroYearNanotime)<<bitshiftYearNanotime, nil
+}
+
+func (time Nanotime) WithMonth(month int, policy DayPolicy) (Nanotime, error) {
+	if err := checkRange("month", month, 1, 12); err != nil {
+		return time, err
+	}
+	day, err := applyDayPolicy(policy, time.Year(), month, time.Day())
+	if err != nil {
+		return time, err
+	}
+	time = time&^maskDayNanotime | Nanotime(day)<<bitshiftDayNanotime
+	return time&^maskMonthNanotime | Nanotime(month)<<bitshiftMonthNanotime, nil
+}
+
+// WithDay sets the day of month, which must exist in the current month.
+func (time Nanotime) WithDay(day int) (Nanotime, error) {
+	max := 31
+	if month := time.Month(); month >= 1 && month <= 12 {
+		max = daysInMonth(time.Year(), month)
+	}
+	if err := checkRange("day", day, 1, max); err != nil {
+		return time, err
+	}
+	return time&^maskDayNanotime | Nanotime(day)<<bitshiftDayNanotime, nil
+}
+
+func (time Nanotime) WithHour(hour int) (Nanotime, error) {
+	if err := checkRange("hour", hour, 0, 23); err != nil {
+		return time, err
+	}
+	return time&^maskHourNanotime | Nanotime(hour)<<bitshiftHourNanotime, nil
+}
+
+func (time Nanotime) WithMinute(minute int) (Nanotime, error) {
+	if err := checkRange("minute", minute, 0, 59); err != nil {
+		return time, err
+	}
+	return time&^maskMinuteNanotime | Nanotime(minute)<<bitshiftMinuteNanotime, nil
+}
+
+// WithSecond sets the second, allowing 60 for leap seconds.
+func (time Nanotime) WithSecond(second int) (Nanotime, error) {
+	if err := checkRange("second", second, 0, 60); err != nil {
+		return time, err
+	}
+	return time&^maskSecondNanotime | Nanotime(second)<<bitshiftSecondNanotime, nil
+}
@@ -3,6 +3,8 @@ package smalltime
 import "testing"
 import "time"
 
+import "errors"
+
 //import "fmt"
 
 func assertEncodeDecodeNanotime(t *testing.T, year, month, day, hour, minute, second, nsec int) {
@@ -289,3 +291,172 @@ func TestSpecExamplesNanotime(t *testing.T) {
 	assertEncodeNanotime(t, 1985, 10, 27, 8, 22, 16, 123900142, Nanotime(0x0fada164076290ee))
 	assertEncodeNanotime(t, 1985, 10, 26, 8, 21, 16, 123900142, Nanotime(0x0fad2154076290ee))
 }
+
+func TestSubsecondAccessorsNanotime(t *testing.T) {
+	time := NewNanotime(2003, 11, 15, 8, 30, 55, 123456789)
+	if time.Millisecond() != 123 || time.Microsecond() != 123456 || time.Nanosecond() != 123456789 {
+		t.Errorf("Expected 123 / 123456 / 123456789 but got %v / %v / %v",
+			time.Millisecond(), time.Microsecond(), time.Nanosecond())
+	}
+	for digits, expected := range []int{0, 1, 12, 123, 1234, 12345, 123456, 1234567, 12345678, 123456789} {
+		if actual := time.Fraction(digits); actual != expected {
+			t.Errorf("Expected Fraction(%v) to be %v but got %v", digits, expected, actual)
+		}
+	}
+}
+
+func TestSubsecondSettersNanotime(t *testing.T) {
+	time := NewNanotime(2003, 11, 15, 8, 30, 55, 123456789)
+	assertWithSubsecondNanotime(t, time.WithMillisecond, 999, NewNanotime(2003, 11, 15, 8, 30, 55, 999000000))
+	assertWithSubsecondNanotime(t, time.WithMicrosecond, 1, NewNanotime(2003, 11, 15, 8, 30, 55, 1000))
+	assertWithSubsecondNanotime(t, time.WithNanosecond, 999999999, NewNanotime(2003, 11, 15, 8, 30, 55, 999999999))
+
+	assertWithSubsecondErrorNanotime(t, time.WithMillisecond, -1)
+	assertWithSubsecondErrorNanotime(t, time.WithMicrosecond, 1000000)
+	assertWithSubsecondErrorNanotime(t, time.WithNanosecond, 1000000000)
+}
+
+func assertWithSubsecondNanotime(t *testing.T, setter func(int) (Nanotime, error), value int, expected Nanotime) {
+	actual, err := setter(value)
+	if err != nil {
+		t.Errorf("Unexpected error setting %v: %v", value, err)
+	} else if actual != expected {
+		t.Errorf("Expected setting %v to give %016x but got %016x", value, expected, actual)
+	}
+}
+
+func assertWithSubsecondErrorNanotime(t *testing.T, setter func(int) (Nanotime, error), value int) {
+	if _, err := setter(value); err == nil {
+		t.Errorf("Expected error setting %v", value)
+	}
+}
+
+func assertWithNanotime(t *testing.T, actual Nanotime, err error, expected Nanotime) {
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	} else if actual != expected {
+		t.Errorf("Expected %016x but got %016x", expected, actual)
+	}
+}
+
+func assertWithErrorNanotime(t *testing.T, actual Nanotime, err error, original Nanotime) {
+	if err == nil {
+		t.Errorf("Expected an error but got %016x", actual)
+	} else if actual != original {
+		t.Errorf("Expected %016x to be unchanged on error but got %016x", original, actual)
+	}
+}
+
+func TestFieldSettersNanotime(t *testing.T) {
+	time := NewNanotime(2003, 11, 15, 8, 30, 55, 1402778)
+
+	v, err := time.WithYear(2225, KeepDay)
+	assertWithNanotime(t, v, err, NewNanotime(2225, 11, 15, 8, 30, 55, 1402778))
+	v, err = time.WithMonth(2, KeepDay)
+	assertWithNanotime(t, v, err, NewNanotime(2003, 2, 15, 8, 30, 55, 1402778))
+	v, err = time.WithDay(1)
+	assertWithNanotime(t, v, err, NewNanotime(2003, 11, 1, 8, 30, 55, 1402778))
+	v, err = time.WithHour(0)
+	assertWithNanotime(t, v, err, NewNanotime(2003, 11, 15, 0, 30, 55, 1402778))
+	v, err = time.WithMinute(59)
+	assertWithNanotime(t, v, err, NewNanotime(2003, 11, 15, 8, 59, 55, 1402778))
+	v, err = time.WithSecond(60)
+	assertWithNanotime(t, v, err, NewNanotime(2003, 11, 15, 8, 30, 60, 1402778))
+
+	v, err = time.WithYear(1969, KeepDay)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithYear(2226, KeepDay)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithMonth(0, KeepDay)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithDay(0)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithHour(24)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithMinute(60)
+	assertWithErrorNanotime(t, v, err, time)
+	v, err = time.WithSecond(-1)
+	assertWithErrorNanotime(t, v, err, time)
+}
+
+func TestDayPolicyNanotime(t *testing.T) {
+	mar31 := NewNanotime(2001, 3, 31, 12, 0, 0, 0)
+
+	v, err := mar31.WithMonth(4, KeepDay)
+	assertWithNanotime(t, v, err, NewNanotime(2001, 4, 31, 12, 0, 0, 0))
+	v, err = mar31.WithMonth(4, CheckDay)
+	assertWithErrorNanotime(t, v, err, mar31)
+	v, err = mar31.WithMonth(2, ClampDay)
+	assertWithNanotime(t, v, err, NewNanotime(2001, 2, 28, 12, 0, 0, 0))
+
+	feb29 := NewNanotime(2000, 2, 29, 12, 0, 0, 0)
+	v, err = feb29.WithYear(2100, CheckDay)
+	assertWithErrorNanotime(t, v, err, feb29)
+	v, err = feb29.WithYear(2100, ClampDay)
+	assertWithNanotime(t, v, err, NewNanotime(2100, 2, 28, 12, 0, 0, 0))
+
+	month15 := NewNanotime(2000, 15, 1, 0, 0, 0, 0)
+	v, err = month15.WithYear(2001, ClampDay)
+	assertWithErrorNanotime(t, v, err, month15)
+}
+
+func TestMinMaxNanotime(t *testing.T) {
+	assertDecodeNanotime(t, MinNanotime, 1970, 1, 1, 0, 0, 0, 0)
+	assertDecodeNanotime(t, MaxNanotime, 2225, 12, 31, 23, 59, 59, 999999999)
+	if err := MinNanotime.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := MaxNanotime.Validate(); err != nil {
+		t.Error(err)
+	}
+	if MinNanotime.AsTime().Unix() != 0 {
+		t.Errorf("Expected MinNanotime to be the unix epoch but got %v", MinNanotime.AsTime())
+	}
+}
+
+func TestNanotimeFromTimeChecked(t *testing.T) {
+	gotime := time.Date(1999, 2, 15, 12, 8, 45, 10159122, time.UTC)
+	if value, err := NanotimeFromTimeChecked(gotime); err != nil || value != NewNanotime(1999, 2, 15, 12, 8, 45, 10159122) {
+		t.Errorf("Unexpected result 0x%016x (error %v)", uint64(value), err)
+	}
+	if value, err := NanotimeFromTimeChecked(time.Unix(0, 0)); err != nil || value != MinNanotime {
+		t.Errorf("Unexpected result 0x%016x (error %v)", uint64(value), err)
+	}
+
+	for _, gotime := range []time.Time{
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(2226, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2300, 6, 1, 0, 0, 0, 0, time.UTC),
+		// Still 1969 in UTC
+		time.Date(1970, 1, 1, 0, 30, 0, 0, time.FixedZone("", 3600)),
+	} {
+		if _, err := NanotimeFromTimeChecked(gotime); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected %v to give ErrOutOfRange but got %v", gotime, err)
+		}
+	}
+}
+
+func TestNanotimeFromTimeSaturating(t *testing.T) {
+	if value := NanotimeFromTimeSaturating(time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)); value != MinNanotime {
+		t.Errorf("Expected MinNanotime but got 0x%016x", uint64(value))
+	}
+	if value := NanotimeFromTimeSaturating(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)); value != MaxNanotime {
+		t.Errorf("Expected MaxNanotime but got 0x%016x", uint64(value))
+	}
+	for _, bound := range []Nanotime{MinNanotime, MaxNanotime} {
+		if value, err := NanotimeFromTimeChecked(bound.AsTime()); err != nil || value != bound {
+			t.Errorf("Expected 0x%016x to round trip but got 0x%016x (error %v)", uint64(bound), uint64(value), err)
+		}
+	}
+	gotime := time.Date(2225, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	if value := NanotimeFromTimeSaturating(gotime); value != NewNanotime(2225, 12, 31, 23, 59, 59, 999999999) {
+		t.Errorf("Unexpected result 0x%016x", uint64(value))
+	}
+}
+
+func TestRangeErrorIsErrOutOfRange(t *testing.T) {
+	_, err := NewNanotime(2000, 1, 1, 0, 0, 0, 0).WithHour(24)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected %v to match ErrOutOfRange", err)
+	}
+}
@@ -0,0 +1,425 @@
+package smalltime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Period is an ISO 8601 duration such as P1Y2M3DT4H5M6.5S. Years, months and
+// days are calendar units: adding P1M to Jan 31 gives the last day of
+// February, and P1D always advances the date by one. Hours, minutes, seconds
+// and nanoseconds are elapsed clock time.
+//
+// Components may be negative. A period whose components are all zero or
+// negative is written with a leading minus sign (-P1D); one with mixed signs
+// has a sign on each negative component (P1M-3D).
+type Period struct {
+	Years       int
+	Months      int
+	Days        int
+	Hours       int
+	Minutes     int
+	Seconds     int
+	Nanoseconds int
+}
+
+// IsZero reports whether all components of the period are zero.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Negate returns the period with every component negated.
+func (p Period) Negate() Period {
+	return Period{-p.Years, -p.Months, -p.Days, -p.Hours, -p.Minutes, -p.Seconds, -p.Nanoseconds}
+}
+
+func (p Period) components() []int {
+	return []int{p.Years, p.Months, p.Days, p.Hours, p.Minutes, p.Seconds, p.Nanoseconds}
+}
+
+func (p Period) hasClock() bool {
+	return p.Hours != 0 || p.Minutes != 0 || p.Seconds != 0 || p.Nanoseconds != 0
+}
+
+func (p Period) clockNanos() int64 {
+	return ((int64(p.Hours)*60+int64(p.Minutes))*60+int64(p.Seconds))*1e9 + int64(p.Nanoseconds)
+}
+
+// Calendar components spanning more than Smalltime's whole range are rejected,
+// which keeps the date arithmetic from overflowing.
+const maxPeriodMonths = (maxYearSmalltime - minYearSmalltime + 1) * 12
+
+var maxPeriodDays = daysFromCivil(maxYearSmalltime+1, 1, 1) - daysFromCivil(minYearSmalltime, 1, 1)
+
+// Returns an error if the clock components don't fit into clockNanos (about
+// 292 years), or the months or days span more than Smalltime's range.
+func (p Period) checkOverflow() error {
+	fail := func() error {
+		return fmt.Errorf("smalltime: period %v is out of range: %w", p, ErrOutOfRange)
+	}
+	months, overflow := addMultiples(0, p.Years, 12)
+	if !overflow {
+		months, overflow = addMultiples(months, p.Months, 1)
+	}
+	if overflow || months > maxPeriodMonths || months < -maxPeriodMonths {
+		return fail()
+	}
+	if days := int64(p.Days); days > maxPeriodDays || days < -maxPeriodDays {
+		return fail()
+	}
+	total := int64(0)
+	for _, component := range []struct {
+		value int
+		unit  int64
+	}{{p.Hours, 3600e9}, {p.Minutes, 60e9}, {p.Seconds, 1e9}, {p.Nanoseconds, 1}} {
+		var overflow bool
+		if total, overflow = addMultiples(total, component.value, component.unit); overflow {
+			return fail()
+		}
+	}
+	return nil
+}
+
+// Returns total + value*unit, and whether it overflowed.
+func addMultiples(total int64, value int, unit int64) (int64, bool) {
+	v := int64(value)
+	if v > math.MaxInt64/unit || v < math.MinInt64/unit {
+		return 0, true
+	}
+	product := v * unit
+	if (product > 0 && total > math.MaxInt64-product) || (product < 0 && total < math.MinInt64-product) {
+		return 0, true
+	}
+	return total + product, false
+}
+
+// Normalized folds months into years and nanoseconds, seconds and minutes into
+// the larger clock units, so that each component is within its usual range
+// and all have the same sign as their group's total. Days and hours are left
+// separate because a day is a calendar unit.
+func (p Period) Normalized() Period {
+	months := p.Years*12 + p.Months
+	return Period{
+		Years:  months / 12,
+		Months: months % 12,
+		Days:   p.Days,
+	}.withClockNanos(p.clockNanos())
+}
+
+// String formats the period in ISO 8601 form (a zero period is PT0S).
+func (p Period) String() string {
+	if p.IsZero() {
+		return "PT0S"
+	}
+
+	negative := true
+	for _, component := range p.components() {
+		if component > 0 {
+			negative = false
+		}
+	}
+	if negative {
+		return "-" + p.Negate().String()
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('P')
+	appendComponent := func(value int, designator byte) {
+		if value != 0 {
+			sb.WriteString(strconv.Itoa(value))
+			sb.WriteByte(designator)
+		}
+	}
+	appendComponent(p.Years, 'Y')
+	appendComponent(p.Months, 'M')
+	appendComponent(p.Days, 'D')
+	if !p.hasClock() {
+		return sb.String()
+	}
+
+	sb.WriteByte('T')
+	appendComponent(p.Hours, 'H')
+	appendComponent(p.Minutes, 'M')
+	if p.Seconds != 0 || p.Nanoseconds != 0 {
+		seconds, nanos := p.Seconds, p.Nanoseconds
+		// Combine signs so that 1s + -500ms renders as 0.5S
+		total := int64(seconds)*1e9 + int64(nanos)
+		if total < 0 {
+			sb.WriteByte('-')
+			total = -total
+		}
+		sb.WriteString(strconv.FormatInt(total/1e9, 10))
+		if fraction := total % 1e9; fraction != 0 {
+			sb.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", fraction), "0"))
+		}
+		sb.WriteByte('S')
+	}
+	return sb.String()
+}
+
+// ParsePeriod parses an ISO 8601 duration such as P1Y2M3DT4H5M6.5S or P2W.
+// A leading minus sign negates the whole period, and individual components
+// may also be negative. Only the seconds may have a fraction (of up to 9
+// digits, with '.' or ',').
+func ParsePeriod(str string) (p Period, err error) {
+	fail := func(reason string) (Period, error) {
+		return Period{}, fmt.Errorf("smalltime: cannot parse period %q: %v", str, reason)
+	}
+
+	rest := str
+	negate := false
+	if strings.HasPrefix(rest, "-") {
+		negate = true
+		rest = rest[1:]
+	} else if strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+	}
+	if !strings.HasPrefix(rest, "P") && !strings.HasPrefix(rest, "p") {
+		return fail("expected 'P'")
+	}
+	rest = rest[1:]
+
+	// Designators must appear in this order, and each at most once.
+	const dateDesignators = "YMWD"
+	const timeDesignators = "HMS"
+	designators := dateDesignators
+	inTime := false
+	found := false
+	for len(rest) > 0 {
+		if rest[0] == 'T' || rest[0] == 't' {
+			if inTime {
+				return fail("duplicate 'T'")
+			}
+			inTime = true
+			designators = timeDesignators
+			rest = rest[1:]
+			if len(rest) == 0 {
+				return fail("expected a time component after 'T'")
+			}
+			continue
+		}
+
+		end := 0
+		if end < len(rest) && (rest[end] == '-' || rest[end] == '+') {
+			end++
+		}
+		digitsStart := end
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		if end == digitsStart {
+			return fail("expected a number")
+		}
+		value, convErr := strconv.Atoi(rest[:end])
+		if convErr != nil {
+			return fail("number out of range")
+		}
+
+		nanos := 0
+		hasFraction := false
+		if end < len(rest) && (rest[end] == '.' || rest[end] == ',') {
+			hasFraction = true
+			fractionStart := end + 1
+			end = fractionStart
+			for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+				end++
+			}
+			digits := rest[fractionStart:end]
+			if len(digits) == 0 || len(digits) > 9 {
+				return fail("expected 1 to 9 fractional digits")
+			}
+			nanos, _ = strconv.Atoi(digits + strings.Repeat("0", 9-len(digits)))
+			if rest[0] == '-' {
+				nanos = -nanos
+			}
+		}
+
+		if end >= len(rest) {
+			return fail("missing designator")
+		}
+		designator := rest[end] &^ 0x20 // Upper case
+		index := strings.IndexByte(designators, designator)
+		if index < 0 {
+			return fail(fmt.Sprintf("unexpected designator '%c'", rest[end]))
+		}
+		designators = designators[index+1:]
+		if hasFraction && !(inTime && designator == 'S') {
+			return fail("only seconds may have a fraction")
+		}
+
+		switch {
+		case !inTime && designator == 'Y':
+			p.Years = value
+		case !inTime && designator == 'M':
+			p.Months = value
+		case !inTime && (designator == 'W' || designator == 'D'):
+			unit := int64(1)
+			if designator == 'W' {
+				unit = 7
+			}
+			days, overflow := addMultiples(int64(p.Days), value, unit)
+			if overflow || days > math.MaxInt || days < math.MinInt {
+				return fail("number out of range")
+			}
+			p.Days = int(days)
+		case designator == 'H':
+			p.Hours = value
+		case designator == 'M':
+			p.Minutes = value
+		case designator == 'S':
+			p.Seconds, p.Nanoseconds = value, nanos
+		}
+		found = true
+		rest = rest[end+1:]
+	}
+	if !found {
+		return fail("expected at least one component")
+	}
+
+	if negate {
+		p = p.Negate()
+	}
+	if p.checkOverflow() != nil {
+		return fail("components out of range")
+	}
+	return p, nil
+}
+
+// Date & time fields used for period arithmetic. nanosOfDay may reach
+// 86400e9 + 999999999 for a leap second.
+type periodFields struct {
+	year, month, day int
+	nanosOfDay       int64
+}
+
+func newPeriodFields[T Timestamp](t T) periodFields {
+	return periodFields{
+		year:       t.Year(),
+		month:      t.Month(),
+		day:        t.Day(),
+		nanosOfDay: ((int64(t.Hour())*60+int64(t.Minute()))*60+int64(t.Second()))*1e9 + int64(t.SubsecondNanos()),
+	}
+}
+
+func (f periodFields) addMonths(months int) periodFields {
+	totalMonths := int64(f.year)*12 + int64(f.month-1) + int64(months)
+	year, month := floorDivMod(totalMonths, 12)
+	f.year, f.month = int(year), int(month)+1
+	if max := daysInMonth(f.year, f.month); f.day > max {
+		f.day = max
+	}
+	return f
+}
+
+func (f periodFields) addDays(days int64) periodFields {
+	f.year, f.month, f.day = civilFromDays(daysFromCivil(f.year, f.month, f.day) + days)
+	return f
+}
+
+func (f periodFields) add(p Period) periodFields {
+	f = f.addMonths(p.Years*12 + p.Months)
+	if p.Days != 0 {
+		f = f.addDays(int64(p.Days))
+	}
+	if p.hasClock() {
+		// A leap second counts as the first second of the next minute here.
+		clockDays, clockNanos := floorDivMod(p.clockNanos(), nanosecondsPerDay)
+		days, nanos := floorDivMod(f.nanosOfDay+clockNanos, nanosecondsPerDay)
+		f = f.addDays(clockDays + days)
+		f.nanosOfDay = nanos
+	}
+	return f
+}
+
+func (f periodFields) clock() (hour, minute, second, nanosecond int) {
+	nanos := f.nanosOfDay
+	if nanos >= nanosecondsPerDay {
+		// Leap second
+		return 23, 59, 60, int(nanos % 1e9)
+	}
+	return int(nanos / 3600e9), int(nanos / 60e9 % 60), int(nanos / 1e9 % 60), int(nanos % 1e9)
+}
+
+// AddPeriod adds a period, first applying years and months (clamping the day
+// to the end of a shorter month), then days, then the clock components.
+// Sub-microsecond precision is truncated. If the period has clock components,
+// a leap second is treated as the first second of the following minute.
+func (time Smalltime) AddPeriod(p Period) (Smalltime, error) {
+	if err := p.checkOverflow(); err != nil {
+		return 0, err
+	}
+	f := newPeriodFields(time).add(p)
+	if err := checkRange("year", f.year, minYearSmalltime, maxYearSmalltime); err != nil {
+		return 0, err
+	}
+	hour, minute, second, nanosecond := f.clock()
+	return NewSmalltime(f.year, f.month, f.day, hour, minute, second, nanosecond/1000), nil
+}
+
+// AddPeriod adds a period, first applying years and months (clamping the day
+// to the end of a shorter month), then days, then the clock components. If
+// the period has clock components, a leap second is treated as the first
+// second of the following minute.
+func (time Nanotime) AddPeriod(p Period) (Nanotime, error) {
+	if err := p.checkOverflow(); err != nil {
+		return 0, err
+	}
+	f := newPeriodFields(time).add(p)
+	if err := checkRange("year", f.year, minYearNanotime, maxYearNanotime); err != nil {
+		return 0, err
+	}
+	hour, minute, second, nanosecond := f.clock()
+	return NewNanotime(f.year, f.month, f.day, hour, minute, second, nanosecond), nil
+}
+
+// Between returns the calendar difference from a to b: the largest number of
+// whole months, then whole days, then the remaining clock time, so that
+// a.AddPeriod(Between(a, b)) == b. If b is before a, the months are counted
+// backwards from a and every component is zero or negative; because months
+// have different lengths, this isn't always the negation of Between(b, a).
+// When b is a leap second, the clock part can't land on it exactly, and the
+// result ends at the following second.
+func Between[T Timestamp](a, b T) Period {
+	from, to := newPeriodFields(a), newPeriodFields(b)
+	direction := int64(1)
+	if Compare(a, b) > 0 {
+		direction = -1
+	}
+	// Reports whether x is beyond y in the direction of travel.
+	beyond := func(x, y periodFields) bool {
+		if dx, dy := daysFromCivil(x.year, x.month, x.day), daysFromCivil(y.year, y.month, y.day); dx != dy {
+			return (dx-dy)*direction > 0
+		}
+		return (x.nanosOfDay-y.nanosOfDay)*direction > 0
+	}
+
+	months := (to.year-from.year)*12 + to.month - from.month
+	middle := from.addMonths(months)
+	if beyond(middle, to) {
+		months -= int(direction)
+		middle = from.addMonths(months)
+	}
+
+	days := daysFromCivil(to.year, to.month, to.day) - daysFromCivil(middle.year, middle.month, middle.day)
+	nanos := to.nanosOfDay - middle.nanosOfDay
+	if nanos*direction < 0 {
+		days -= direction
+		nanos += direction * nanosecondsPerDay
+	}
+
+	return Period{
+		Years:  months / 12,
+		Months: months % 12,
+		Days:   int(days),
+	}.withClockNanos(nanos)
+}
+
+func (p Period) withClockNanos(nanos int64) Period {
+	p.Hours = int(nanos / 3600e9)
+	p.Minutes = int(nanos / 60e9 % 60)
+	p.Seconds = int(nanos / 1e9 % 60)
+	p.Nanoseconds = int(nanos % 1e9)
+	return p
+}
@@ -0,0 +1,158 @@
+package smalltime
+
+import (
+	"math"
+	"testing"
+)
+
+func assertParsePeriod(t *testing.T, str string, expected Period) {
+	actual, err := ParsePeriod(str)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", str, err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %v to parse as %+v but got %+v", str, expected, actual)
+	}
+}
+
+func assertPeriodString(t *testing.T, p Period, expected string) {
+	if actual := p.String(); actual != expected {
+		t.Errorf("Expected %+v to format as %v but got %v", p, expected, actual)
+	}
+	if parsed, err := ParsePeriod(expected); err != nil || parsed.Normalized() != p.Normalized() {
+		t.Errorf("Expected %v to parse back as %+v but got %+v (error %v)", expected, p, parsed, err)
+	}
+}
+
+func assertAddPeriod(t *testing.T, start Smalltime, period string, expected Smalltime) {
+	p, err := ParsePeriod(period)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	actual, err := start.AddPeriod(p)
+	if err != nil {
+		t.Errorf("Unexpected error adding %v to %v: %v", period, Format(start), err)
+		return
+	}
+	if actual != expected {
+		t.Errorf("Expected %v + %v to be %v but got %v", Format(start), period, Format(expected), Format(actual))
+	}
+}
+
+func assertBetween(t *testing.T, a, b Smalltime, expected string) {
+	p := Between(a, b)
+	if actual := p.String(); actual != expected {
+		t.Errorf("Expected Between(%v, %v) to be %v but got %v", Format(a), Format(b), expected, actual)
+	}
+	if sum, err := a.AddPeriod(p); err != nil || sum != b {
+		t.Errorf("Expected %v + %v to be %v but got %v (error %v)", Format(a), p, Format(b), Format(sum), err)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	assertParsePeriod(t, "P1Y2M3DT4H5M6.5S", Period{1, 2, 3, 4, 5, 6, 500000000})
+	assertParsePeriod(t, "P1M", Period{Months: 1})
+	assertParsePeriod(t, "PT1M", Period{Minutes: 1})
+	assertParsePeriod(t, "P2W", Period{Days: 14})
+	assertParsePeriod(t, "P1W2D", Period{Days: 9})
+	assertParsePeriod(t, "PT0,000000001S", Period{Nanoseconds: 1})
+	assertParsePeriod(t, "pt36h", Period{Hours: 36})
+	assertParsePeriod(t, "-P1DT1H", Period{Days: -1, Hours: -1})
+	assertParsePeriod(t, "P1M-3D", Period{Months: 1, Days: -3})
+	assertParsePeriod(t, "PT-0.5S", Period{Nanoseconds: -500000000})
+	assertParsePeriod(t, "+P0D", Period{})
+
+	for _, str := range []string{"", "P", "PT", "1D", "P1", "P1DT", "PD", "P1D1Y", "P1Y1Y", "PT1D", "P1H",
+		"P1.5D", "PT1.5M", "PT1.S", "PT1.1234567890S", "P1DTT1H", "P99999999999999999999Y",
+		"PT3000000H", "-PT3000000H", "PT2562047H59M", "P1317624576693539401W", "P9223372036854775807W",
+		"P1W9223372036854775807D", "P100000000000D", "P400000Y", "P1Y9223372036854775807M"} {
+		if _, err := ParsePeriod(str); err == nil {
+			t.Errorf("Expected parsing %q to fail", str)
+		}
+	}
+}
+
+func TestPeriodString(t *testing.T) {
+	assertPeriodString(t, Period{}, "PT0S")
+	assertPeriodString(t, Period{1, 2, 3, 4, 5, 6, 500000000}, "P1Y2M3DT4H5M6.5S")
+	assertPeriodString(t, Period{Days: 3}, "P3D")
+	assertPeriodString(t, Period{Hours: 1}, "PT1H")
+	assertPeriodString(t, Period{Nanoseconds: 1}, "PT0.000000001S")
+	assertPeriodString(t, Period{Days: -1, Hours: -2}, "-P1DT2H")
+	assertPeriodString(t, Period{Months: 1, Days: -3}, "P1M-3D")
+	assertPeriodString(t, Period{Seconds: 1, Nanoseconds: -500000000}, "PT0.5S")
+	assertPeriodString(t, Period{Minutes: 1, Nanoseconds: -500000000}, "PT1M-0.5S")
+}
+
+func TestPeriodNormalized(t *testing.T) {
+	p := Period{Years: 1, Months: 14, Days: 40, Hours: 25, Minutes: 61, Seconds: 61, Nanoseconds: 1500000000}
+	expected := Period{Years: 2, Months: 2, Days: 40, Hours: 26, Minutes: 2, Seconds: 2, Nanoseconds: 500000000}
+	if actual := p.Normalized(); actual != expected {
+		t.Errorf("Expected %+v but got %+v", expected, actual)
+	}
+	p = Period{Years: 1, Months: -1, Hours: 1, Seconds: -1}
+	expected = Period{Months: 11, Minutes: 59, Seconds: 59}
+	if actual := p.Normalized(); actual != expected {
+		t.Errorf("Expected %+v but got %+v", expected, actual)
+	}
+	if actual := p.Negate().Normalized(); actual != expected.Negate() {
+		t.Errorf("Expected %+v but got %+v", expected.Negate(), actual)
+	}
+}
+
+func TestAddPeriod(t *testing.T) {
+	assertAddPeriod(t, NewSmalltime(2019, 1, 31, 12, 0, 0, 0), "P1M", NewSmalltime(2019, 2, 28, 12, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2020, 1, 31, 12, 0, 0, 0), "P1M", NewSmalltime(2020, 2, 29, 12, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2020, 2, 29, 0, 0, 0, 0), "P1Y", NewSmalltime(2021, 2, 28, 0, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2019, 12, 31, 23, 0, 0, 0), "PT1H", NewSmalltime(2020, 1, 1, 0, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2019, 12, 31, 23, 0, 0, 0), "P1Y2M3DT4H5M6.5S", NewSmalltime(2021, 3, 4, 3, 5, 6, 500000))
+	assertAddPeriod(t, NewSmalltime(2019, 3, 1, 0, 0, 0, 0), "-P1D", NewSmalltime(2019, 2, 28, 0, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2019, 3, 1, 0, 0, 0, 0), "-PT0.000001S", NewSmalltime(2019, 2, 28, 23, 59, 59, 999999))
+	assertAddPeriod(t, NewSmalltime(0, 3, 1, 0, 0, 0, 0), "-P1D", NewSmalltime(0, 2, 29, 0, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(1, 1, 1, 0, 0, 0, 0), "-P1Y", NewSmalltime(0, 1, 1, 0, 0, 0, 0))
+	assertAddPeriod(t, NewSmalltime(2016, 12, 31, 23, 59, 60, 0), "P1D", NewSmalltime(2017, 1, 1, 23, 59, 60, 0))
+	assertAddPeriod(t, NewSmalltime(2016, 12, 31, 23, 59, 60, 0), "PT1S", NewSmalltime(2017, 1, 1, 0, 0, 1, 0))
+	assertAddPeriod(t, NewSmalltime(2000, 1, 1, 0, 0, 0, 0), "PT0.0000019S", NewSmalltime(2000, 1, 1, 0, 0, 0, 1))
+
+	if _, err := MaxSmalltime.AddPeriod(Period{Years: 1}); err == nil {
+		t.Errorf("Expected overflowing the year range to fail")
+	}
+
+	nano, err := NewNanotime(2000, 1, 31, 0, 0, 0, 999999999).AddPeriod(Period{Months: 1, Nanoseconds: 1})
+	if err != nil || nano != NewNanotime(2000, 2, 29, 0, 0, 1, 0) {
+		t.Errorf("Unexpected result %v (error %v)", Format(nano), err)
+	}
+	if _, err := MinNanotime.AddPeriod(Period{Nanoseconds: -1}); err == nil {
+		t.Errorf("Expected going before 1970 to fail")
+	}
+	if _, err := NewSmalltime(2000, 1, 1, 0, 0, 0, 0).AddPeriod(Period{Hours: 3000000}); err == nil {
+		t.Errorf("Expected overflowing the clock components to fail")
+	}
+	if _, err := NewSmalltime(2000, 1, 1, 0, 0, 0, 0).AddPeriod(Period{Days: math.MaxInt64}); err == nil {
+		t.Errorf("Expected overflowing the days to fail")
+	}
+	if _, err := NewSmalltime(2000, 1, 1, 0, 0, 0, 0).AddPeriod(Period{Years: 1, Months: math.MaxInt64}); err == nil {
+		t.Errorf("Expected overflowing the months to fail")
+	}
+	assertAddPeriod(t, MinSmalltime, "P95746129D", NewSmalltime(131071, 12, 31, 0, 0, 0, 0))
+}
+
+func TestBetween(t *testing.T) {
+	assertBetween(t, NewSmalltime(2019, 1, 1, 0, 0, 0, 0), NewSmalltime(2019, 1, 1, 0, 0, 0, 0), "PT0S")
+	assertBetween(t, NewSmalltime(2019, 1, 31, 0, 0, 0, 0), NewSmalltime(2019, 2, 28, 0, 0, 0, 0), "P1M")
+	assertBetween(t, NewSmalltime(2019, 1, 15, 12, 0, 0, 0), NewSmalltime(2019, 3, 15, 11, 0, 0, 0), "P1M27DT23H")
+	assertBetween(t, NewSmalltime(2019, 12, 31, 23, 0, 0, 0), NewSmalltime(2021, 3, 4, 3, 5, 6, 500000), "P1Y2M3DT4H5M6.5S")
+	assertBetween(t, NewSmalltime(-1, 12, 31, 0, 0, 0, 0), NewSmalltime(1, 1, 1, 0, 0, 0, 1), "P1Y1DT0.000001S")
+	assertBetween(t, NewSmalltime(2019, 3, 1, 0, 0, 0, 0), NewSmalltime(2019, 1, 1, 0, 0, 0, 0), "-P2M")
+	assertBetween(t, NewSmalltime(2019, 3, 1, 0, 0, 0, 0), NewSmalltime(2019, 2, 28, 23, 0, 0, 0), "-PT1H")
+	assertBetween(t, NewSmalltime(2019, 3, 31, 0, 0, 0, 0), NewSmalltime(2019, 2, 28, 0, 0, 0, 0), "-P1M")
+	assertBetween(t, NewSmalltime(2019, 3, 31, 12, 0, 0, 0), NewSmalltime(2019, 2, 28, 18, 0, 0, 0), "-P30DT18H")
+
+	nanoA := NewNanotime(2000, 1, 1, 0, 0, 0, 0)
+	nanoB := NewNanotime(2100, 6, 15, 1, 2, 3, 4)
+	if p := Between(nanoA, nanoB); p.String() != "P100Y5M14DT1H2M3.000000004S" {
+		t.Errorf("Unexpected period %v", p)
+	}
+}
@@ -0,0 +1,173 @@
+package smalltime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelativeUnit is a unit of time that a relative phrase can be expressed in.
+type RelativeUnit int
+
+const (
+	RelativeSecond RelativeUnit = iota
+	RelativeMinute
+	RelativeHour
+	RelativeDay
+	RelativeWeek
+	RelativeMonth
+	RelativeYear
+	relativeUnitCount
+)
+
+// RelativeLocale is a table of the phrases used to describe relative times in
+// one language. It is kept apart from Locale because it serves only Humanizer:
+// the phrases come from CLDR's relative-time data rather than its calendar
+// names, need plural rules that formatting and parsing don't, and adding them
+// to the Locale interface would break existing implementations of it.
+type RelativeLocale struct {
+	// Used when the difference is smaller than the granularity.
+	Now string
+	// Wrap the units for times in the past and future, using a single %s.
+	Past   string
+	Future string
+	// Joins units when more than one is shown.
+	Separator string
+	// The plural forms of each unit, each containing a single %d for the count.
+	Units [relativeUnitCount][]string
+	// Plural chooses a form from Units for a count. If nil, the first form is
+	// used for 1 and the second for everything else, as in English.
+	Plural func(count int) int
+}
+
+// EnglishRelative returns a copy of the built-in English locale, which is
+// the default.
+func EnglishRelative() *RelativeLocale {
+	locale := *englishRelative
+	for unit, forms := range locale.Units {
+		locale.Units[unit] = append([]string(nil), forms...)
+	}
+	return &locale
+}
+
+var englishRelative = &RelativeLocale{
+	Now:       "just now",
+	Past:      "%s ago",
+	Future:    "in %s",
+	Separator: ", ",
+	Units: [relativeUnitCount][]string{
+		RelativeSecond: {"%d second", "%d seconds"},
+		RelativeMinute: {"%d minute", "%d minutes"},
+		RelativeHour:   {"%d hour", "%d hours"},
+		RelativeDay:    {"%d day", "%d days"},
+		RelativeWeek:   {"%d week", "%d weeks"},
+		RelativeMonth:  {"%d month", "%d months"},
+		RelativeYear:   {"%d year", "%d years"},
+	},
+}
+
+func (l *RelativeLocale) unit(unit RelativeUnit, count int) string {
+	forms := l.Units[unit]
+	form := 1
+	if l.Plural != nil {
+		form = l.Plural(count)
+	} else if count == 1 {
+		form = 0
+	}
+	if form >= len(forms) {
+		form = len(forms) - 1
+	}
+	if form < 0 {
+		return fmt.Sprint(count)
+	}
+	return fmt.Sprintf(forms[form], count)
+}
+
+// Humanizer describes times relative to each other with approximate phrases
+// such as "3 hours ago" or "in 2 days". The zero value describes times
+// relative to the current time, in English, to the second, using only the
+// largest unit.
+type Humanizer struct {
+	// The smallest unit to show. Differences smaller than this are described
+	// as Locale.Now.
+	Granularity RelativeUnit
+	// The maximum number of units to show, largest first (as in "1 year, 2
+	// months ago"). Zero means one.
+	MaxUnits int
+	// If nil, English is used.
+	Locale *RelativeLocale
+	// Supplies the current time for Relative. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Relative describes t relative to the current time.
+func (h Humanizer) Relative(t Smalltime) string {
+	now := h.Now
+	if now == nil {
+		now = time.Now
+	}
+	return h.RelativeTo(t, SmalltimeFromTimeSaturating(now()))
+}
+
+// RelativeTo describes t relative to now. Units are truncated rather than
+// rounded, and years and months are counted on the calendar (see Between), so
+// that one month after Jan 15 is exactly Feb 15. Zero and infinite values
+// give an empty string.
+func (h Humanizer) RelativeTo(t, now Smalltime) string {
+	if t.IsZero() || now.IsZero() || t.IsInfinite() || now.IsInfinite() {
+		return ""
+	}
+	locale := h.Locale
+	if locale == nil {
+		locale = englishRelative
+	}
+	maxUnits := h.MaxUnits
+	if maxUnits < 1 {
+		maxUnits = 1
+	}
+	granularity := h.Granularity
+	if granularity < RelativeSecond {
+		granularity = RelativeSecond
+	} else if granularity > RelativeYear {
+		granularity = RelativeYear
+	}
+
+	// Always count forward from the earlier time, so that "1 year ago" and
+	// "in 1 year" cover the same calendar span.
+	future := t > now
+	var p Period
+	if future {
+		p = Between(now, t)
+	} else {
+		p = Between(t, now)
+	}
+	counts := [relativeUnitCount]int{
+		RelativeSecond: p.Seconds,
+		RelativeMinute: p.Minutes,
+		RelativeHour:   p.Hours,
+		RelativeDay:    p.Days % 7,
+		RelativeWeek:   p.Days / 7,
+		RelativeMonth:  p.Months,
+		RelativeYear:   p.Years,
+	}
+	if granularity > RelativeWeek {
+		counts[RelativeWeek] = 0
+	} else if granularity == RelativeWeek {
+		counts[RelativeDay] = 0
+	}
+
+	var parts []string
+	for unit := RelativeYear; unit >= granularity && len(parts) < maxUnits; unit-- {
+		if counts[unit] != 0 {
+			parts = append(parts, locale.unit(unit, counts[unit]))
+		}
+	}
+	if len(parts) == 0 {
+		return locale.Now
+	}
+	phrase := strings.Join(parts, locale.Separator)
+	if future {
+		return fmt.Sprintf(locale.Future, phrase)
+	}
+	return fmt.Sprintf(locale.Past, phrase)
+}
@@ -0,0 +1,90 @@
+package smalltime
+
+import (
+	"testing"
+	"time"
+)
+
+func assertRelative(t *testing.T, h Humanizer, value, now Smalltime, expected string) {
+	if actual := h.RelativeTo(value, now); actual != expected {
+		t.Errorf("Expected %v relative to %v to be %q but got %q", Format(value), Format(now), expected, actual)
+	}
+}
+
+func TestRelativeTo(t *testing.T) {
+	now := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	h := Humanizer{}
+	assertRelative(t, h, now, now, "just now")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 15, 4, 4, 500000), now, "just now")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 15, 4, 4, 0), now, "1 second ago")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 15, 4, 50, 0), now, "in 45 seconds")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 12, 0, 0, 0), now, "3 hours ago")
+	assertRelative(t, h, NewSmalltime(2019, 5, 22, 16, 0, 0, 0), now, "in 2 days")
+	assertRelative(t, h, NewSmalltime(2019, 5, 30, 0, 0, 0, 0), now, "in 1 week")
+	assertRelative(t, h, NewSmalltime(2018, 5, 21, 0, 0, 0, 0), now, "11 months ago")
+	assertRelative(t, h, NewSmalltime(2018, 5, 20, 15, 4, 5, 0), now, "1 year ago")
+	assertRelative(t, h, NewSmalltime(2049, 1, 1, 0, 0, 0, 0), now, "in 29 years")
+	assertRelative(t, h, 0, now, "")
+	assertRelative(t, h, PositiveInfinitySmalltime, now, "")
+}
+
+func TestRelativeCalendarBoundaries(t *testing.T) {
+	h := Humanizer{}
+	// A month is counted on the calendar, not as a fixed number of days
+	assertRelative(t, h, NewSmalltime(2019, 2, 15, 0, 0, 0, 0), NewSmalltime(2019, 1, 15, 0, 0, 0, 0), "in 1 month")
+	assertRelative(t, h, NewSmalltime(2019, 2, 14, 23, 59, 59, 0), NewSmalltime(2019, 1, 15, 0, 0, 0, 0), "in 4 weeks")
+	assertRelative(t, h, NewSmalltime(2019, 3, 1, 0, 0, 0, 0), NewSmalltime(2019, 2, 1, 0, 0, 0, 0), "in 1 month")
+	assertRelative(t, h, NewSmalltime(2020, 2, 29, 0, 0, 0, 0), NewSmalltime(2021, 2, 28, 0, 0, 0, 0), "1 year ago")
+	assertRelative(t, h, NewSmalltime(2020, 2, 29, 0, 0, 0, 0), NewSmalltime(2021, 2, 27, 0, 0, 0, 0), "11 months ago")
+	assertRelative(t, h, NewSmalltime(2016, 12, 31, 23, 59, 60, 0), NewSmalltime(2016, 12, 31, 23, 59, 59, 0), "in 1 second")
+}
+
+func TestRelativeGranularity(t *testing.T) {
+	now := NewSmalltime(2019, 5, 20, 15, 4, 5, 0)
+	value := NewSmalltime(2018, 3, 9, 12, 2, 1, 0)
+	assertRelative(t, Humanizer{MaxUnits: 3}, value, now, "1 year, 2 months, 1 week ago")
+	assertRelative(t, Humanizer{MaxUnits: 10}, value, now, "1 year, 2 months, 1 week, 4 days, 3 hours, 2 minutes, 4 seconds ago")
+	assertRelative(t, Humanizer{MaxUnits: 10, Granularity: RelativeDay}, value, now, "1 year, 2 months, 1 week, 4 days ago")
+	assertRelative(t, Humanizer{MaxUnits: 10, Granularity: RelativeMonth}, value, now, "1 year, 2 months ago")
+	assertRelative(t, Humanizer{Granularity: RelativeDay}, NewSmalltime(2019, 5, 20, 1, 0, 0, 0), now, "just now")
+	assertRelative(t, Humanizer{Granularity: RelativeMonth}, NewSmalltime(2019, 6, 10, 1, 0, 0, 0), now, "just now")
+	assertRelative(t, Humanizer{Granularity: RelativeMonth}, NewSmalltime(2019, 7, 10, 1, 0, 0, 0), now, "in 1 month")
+}
+
+func TestRelativeNow(t *testing.T) {
+	h := Humanizer{Now: func() time.Time {
+		return time.Date(2019, 5, 20, 15, 4, 5, 0, time.UTC)
+	}}
+	if actual := h.Relative(NewSmalltime(2019, 5, 20, 13, 0, 0, 0)); actual != "2 hours ago" {
+		t.Errorf("Unexpected result %q", actual)
+	}
+}
+
+func TestRelativeLocale(t *testing.T) {
+	// Polish has separate forms for 1, 2-4 (but not 12-14), and the rest
+	polish := &RelativeLocale{
+		Now:       "teraz",
+		Past:      "%s temu",
+		Future:    "za %s",
+		Separator: " i ",
+		Plural: func(count int) int {
+			switch {
+			case count == 1:
+				return 0
+			case count%10 >= 2 && count%10 <= 4 && (count%100 < 12 || count%100 > 14):
+				return 1
+			}
+			return 2
+		},
+	}
+	polish.Units[RelativeSecond] = []string{"%d sekundę", "%d sekundy", "%d sekund"}
+	polish.Units[RelativeMinute] = []string{"%d minutę", "%d minuty", "%d minut"}
+	polish.Units[RelativeHour] = []string{"%d godzinę", "%d godziny", "%d godzin"}
+
+	now := NewSmalltime(2019, 5, 20, 15, 0, 0, 0)
+	h := Humanizer{Locale: polish, MaxUnits: 2}
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 14, 59, 0, 0), now, "1 minutę temu")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 12, 38, 0, 0), now, "2 godziny i 22 minuty temu")
+	assertRelative(t, h, NewSmalltime(2019, 5, 20, 20, 12, 0, 0), now, "za 5 godzin i 12 minut")
+	assertRelative(t, h, now, now, "teraz")
+}
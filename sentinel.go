@@ -0,0 +1,366 @@
+package smalltime
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The zero value of Smalltime and Nanotime is never a valid date (its month
+// and day are 0), so it serves as the "null" or "unset" value. It marshals to
+// JSON null and SQL NULL. Other values marshal to JSON and SQL as the raw
+// integer, or in ISO 8601 text form through SmalltimeText and NanotimeText.
+//
+// The infinity sentinels (like PostgreSQL's 'infinity' and '-infinity') sort
+// after and before every valid value, and are formatted and parsed as
+// "infinity" and "-infinity". They don't represent real dates, so AsTime and
+// the field accessors return meaningless results for them.
+const (
+	PositiveInfinitySmalltime = Smalltime(math.MaxInt64)
+	NegativeInfinitySmalltime = Smalltime(math.MinInt64)
+
+	PositiveInfinityNanotime = Nanotime(math.MaxUint64)
+	// Nanotime's zero value (null) is taken, so -infinity is the next value up.
+	NegativeInfinityNanotime = Nanotime(1)
+)
+
+const positiveInfinityString = "infinity"
+const negativeInfinityString = "-infinity"
+
+// IsZero reports whether the value is the zero (null/unset) value.
+func (time Smalltime) IsZero() bool {
+	return time == 0
+}
+
+// IsInfinite reports whether the value is PositiveInfinitySmalltime or
+// NegativeInfinitySmalltime.
+func (time Smalltime) IsInfinite() bool {
+	return time == PositiveInfinitySmalltime || time == NegativeInfinitySmalltime
+}
+
+// IsZero reports whether the value is the zero (null/unset) value.
+func (time Nanotime) IsZero() bool {
+	return time == 0
+}
+
+// IsInfinite reports whether the value is PositiveInfinityNanotime or
+// NegativeInfinityNanotime.
+func (time Nanotime) IsInfinite() bool {
+	return time == PositiveInfinityNanotime || time == NegativeInfinityNanotime
+}
+
+// Infinity returns 1 for PositiveInfinitySmalltime, -1 for
+// NegativeInfinitySmalltime, and 0 for all other values.
+func (time Smalltime) Infinity() int {
+	switch time {
+	case PositiveInfinitySmalltime:
+		return 1
+	case NegativeInfinitySmalltime:
+		return -1
+	}
+	return 0
+}
+
+// Infinity returns 1 for PositiveInfinityNanotime, -1 for
+// NegativeInfinityNanotime, and 0 for all other values.
+func (time Nanotime) Infinity() int {
+	switch time {
+	case PositiveInfinityNanotime:
+		return 1
+	case NegativeInfinityNanotime:
+		return -1
+	}
+	return 0
+}
+
+// Returns "infinity" or "-infinity" if t is an infinity sentinel.
+func formatInfinity[T Timestamp](t T) (string, bool) {
+	switch t.Infinity() {
+	case 1:
+		return positiveInfinityString, true
+	case -1:
+		return negativeInfinityString, true
+	}
+	return "", false
+}
+
+// Returns +1 or -1 if str names an infinity sentinel, or 0 otherwise.
+func parseInfinity(str string) int {
+	switch {
+	case strings.EqualFold(str, positiveInfinityString), strings.EqualFold(str, "+"+positiveInfinityString):
+		return 1
+	case strings.EqualFold(str, negativeInfinityString):
+		return -1
+	}
+	return 0
+}
+
+// SmalltimeText is a Smalltime that marshals to text, JSON and SQL in its ISO
+// 8601 form ("1999-02-15T12:08:45.009122Z", "infinity") rather than as the raw
+// integer. Convert a field to SmalltimeText to opt in.
+type SmalltimeText Smalltime
+
+// NanotimeText is a Nanotime that marshals to text, JSON and SQL in its ISO
+// 8601 form ("1999-02-15T12:08:45.009122Z", "infinity") rather than as the raw
+// integer. Convert a field to NanotimeText to opt in.
+type NanotimeText Nanotime
+
+// MarshalText renders the value with Format. The zero value renders as an
+// empty string, and a value with out-of-range fields is an error.
+func (t SmalltimeText) MarshalText() ([]byte, error) {
+	return marshalText(Smalltime(t))
+}
+
+// UnmarshalText accepts anything ParseSmalltime does. An empty string gives
+// the zero value.
+func (t *SmalltimeText) UnmarshalText(text []byte) error {
+	parsed, err := parseSmalltimeText(text)
+	if err == nil {
+		*t = SmalltimeText(parsed)
+	}
+	return err
+}
+
+// MarshalText renders the value with Format. The zero value renders as an
+// empty string, and a value with out-of-range fields is an error.
+func (t NanotimeText) MarshalText() ([]byte, error) {
+	return marshalText(Nanotime(t))
+}
+
+// UnmarshalText accepts anything ParseNanotime does. An empty string gives
+// the zero value.
+func (t *NanotimeText) UnmarshalText(text []byte) error {
+	parsed, err := parseNanotimeText(text)
+	if err == nil {
+		*t = NanotimeText(parsed)
+	}
+	return err
+}
+
+func marshalText[T interface {
+	Timestamp
+	IsZero() bool
+	IsInfinite() bool
+	Validate() error
+}](t T) ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	if !t.IsInfinite() {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(Format(t)), nil
+}
+
+func parseSmalltimeText(text []byte) (Smalltime, error) {
+	if len(text) == 0 {
+		return 0, nil
+	}
+	return ParseSmalltime(string(text))
+}
+
+func parseNanotimeText(text []byte) (Nanotime, error) {
+	if len(text) == 0 {
+		return 0, nil
+	}
+	return ParseNanotime(string(text))
+}
+
+var jsonNull = []byte("null")
+
+// Unmarshals JSON null as the zero value, a number as the raw value, and a
+// string as the text form.
+func unmarshalJSON(data []byte, raw func(json.Number) error, text func([]byte) error) error {
+	if string(data) == string(jsonNull) {
+		return text(nil)
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		return text([]byte(str))
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	return raw(number)
+}
+
+// MarshalJSON renders the zero value as null, and anything else as the raw
+// integer (use SmalltimeText for the text form).
+func (time Smalltime) MarshalJSON() ([]byte, error) {
+	if time.IsZero() {
+		return jsonNull, nil
+	}
+	return strconv.AppendInt(nil, int64(time), 10), nil
+}
+
+// UnmarshalJSON accepts null, the raw integer, or the text form.
+func (time *Smalltime) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, func(number json.Number) error {
+		raw, err := strconv.ParseInt(string(number), 10, 64)
+		if err != nil {
+			return fmt.Errorf("smalltime: cannot unmarshal %v into Smalltime", number)
+		}
+		*time = Smalltime(raw)
+		return nil
+	}, func(text []byte) (err error) {
+		*time, err = parseSmalltimeText(text)
+		return err
+	})
+}
+
+// MarshalJSON renders the zero value as null, and anything else as the raw
+// integer (use NanotimeText for the text form).
+func (time Nanotime) MarshalJSON() ([]byte, error) {
+	if time.IsZero() {
+		return jsonNull, nil
+	}
+	return strconv.AppendUint(nil, uint64(time), 10), nil
+}
+
+// UnmarshalJSON accepts null, the raw integer, or the text form.
+func (time *Nanotime) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, func(number json.Number) error {
+		raw, err := strconv.ParseUint(string(number), 10, 64)
+		if err != nil {
+			return fmt.Errorf("smalltime: cannot unmarshal %v into Nanotime", number)
+		}
+		*time = Nanotime(raw)
+		return nil
+	}, func(text []byte) (err error) {
+		*time, err = parseNanotimeText(text)
+		return err
+	})
+}
+
+func marshalTextJSON(isZero bool, marshalText func() ([]byte, error)) ([]byte, error) {
+	if isZero {
+		return jsonNull, nil
+	}
+	text, err := marshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// MarshalJSON renders the zero value as null, and anything else as a string.
+func (t SmalltimeText) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(t == 0, t.MarshalText)
+}
+
+// UnmarshalJSON accepts null, the text form, or the raw integer.
+func (t *SmalltimeText) UnmarshalJSON(data []byte) error {
+	return (*Smalltime)(t).UnmarshalJSON(data)
+}
+
+// MarshalJSON renders the zero value as null, and anything else as a string.
+func (t NanotimeText) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(t == 0, t.MarshalText)
+}
+
+// UnmarshalJSON accepts null, the text form, or the raw integer.
+func (t *NanotimeText) UnmarshalJSON(data []byte) error {
+	return (*Nanotime)(t).UnmarshalJSON(data)
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL and anything
+// else as the raw integer (use SmalltimeText for the text form).
+func (time Smalltime) Value() (driver.Value, error) {
+	if time.IsZero() {
+		return nil, nil
+	}
+	return int64(time), nil
+}
+
+// Scan implements sql.Scanner, accepting NULL, time.Time, the text form (as
+// a string or []byte) and the raw encoded value (as an int64).
+func (t *Smalltime) Scan(src interface{}) (err error) {
+	switch src := src.(type) {
+	case nil:
+		*t = 0
+	case int64:
+		*t = Smalltime(src)
+	case string:
+		*t, err = parseSmalltimeText([]byte(src))
+	case []byte:
+		*t, err = parseSmalltimeText(src)
+	case time.Time:
+		*t, err = SmalltimeFromTimeChecked(src)
+	default:
+		err = fmt.Errorf("smalltime: cannot scan %T into Smalltime", src)
+	}
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL and anything
+// else as the raw integer. Since SQL has no unsigned 64-bit type, values from
+// 2098 onwards (and PositiveInfinityNanotime) are stored as negative numbers,
+// which Scan converts back. Use NanotimeText for the text form.
+func (time Nanotime) Value() (driver.Value, error) {
+	if time.IsZero() {
+		return nil, nil
+	}
+	return int64(time), nil
+}
+
+// Scan implements sql.Scanner, accepting NULL, time.Time, the text form (as
+// a string or []byte) and the raw encoded value (as an int64).
+func (t *Nanotime) Scan(src interface{}) (err error) {
+	switch src := src.(type) {
+	case nil:
+		*t = 0
+	case int64:
+		*t = Nanotime(src)
+	case string:
+		*t, err = parseNanotimeText([]byte(src))
+	case []byte:
+		*t, err = parseNanotimeText(src)
+	case time.Time:
+		*t, err = NanotimeFromTimeChecked(src)
+	default:
+		err = fmt.Errorf("smalltime: cannot scan %T into Nanotime", src)
+	}
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL and anything
+// else as its text form (which PostgreSQL accepts for timestamp columns,
+// including 'infinity' and '-infinity').
+func (t SmalltimeText) Value() (driver.Value, error) {
+	if t == 0 {
+		return nil, nil
+	}
+	text, err := t.MarshalText()
+	return string(text), err
+}
+
+// Scan implements sql.Scanner, accepting the same sources as Smalltime.Scan.
+func (t *SmalltimeText) Scan(src interface{}) error {
+	return (*Smalltime)(t).Scan(src)
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL and anything
+// else as its text form (which PostgreSQL accepts for timestamp columns,
+// including 'infinity' and '-infinity').
+func (t NanotimeText) Value() (driver.Value, error) {
+	if t == 0 {
+		return nil, nil
+	}
+	text, err := t.MarshalText()
+	return string(text), err
+}
+
+// Scan implements sql.Scanner, accepting the same sources as Nanotime.Scan.
+func (t *NanotimeText) Scan(src interface{}) error {
+	return (*Nanotime)(t).Scan(src)
+}
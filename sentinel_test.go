@@ -0,0 +1,261 @@
+package smalltime
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIsZero(t *testing.T) {
+	var small Smalltime
+	var nano Nanotime
+	if !small.IsZero() || !nano.IsZero() {
+		t.Errorf("Expected the zero values to report IsZero")
+	}
+	if NewSmalltime(0, 1, 1, 0, 0, 0, 0).IsZero() || MinNanotime.IsZero() {
+		t.Errorf("Expected valid dates not to report IsZero")
+	}
+	if small.Validate() == nil || nano.Validate() == nil {
+		t.Errorf("Expected the zero values to be invalid dates")
+	}
+}
+
+func TestInfinitySortsOutsideRange(t *testing.T) {
+	assertGreater(t, PositiveInfinitySmalltime, MaxSmalltime)
+	assertGreater(t, MinSmalltime, NegativeInfinitySmalltime)
+	assertGreater(t, 0, NegativeInfinitySmalltime)
+	assertCompare(t, PositiveInfinitySmalltime, MaxSmalltime, 1)
+	assertCompare(t, NegativeInfinitySmalltime, MinSmalltime, -1)
+
+	assertNanotimeGreater(t, PositiveInfinityNanotime, MaxNanotime)
+	assertNanotimeGreater(t, MinNanotime, NegativeInfinityNanotime)
+	assertCompare(t, PositiveInfinityNanotime, MaxNanotime, 1)
+	assertCompare(t, NegativeInfinityNanotime, MinNanotime, -1)
+
+	for _, value := range []Smalltime{PositiveInfinitySmalltime, NegativeInfinitySmalltime} {
+		if !value.IsInfinite() || value.IsZero() {
+			t.Errorf("Expected 0x%016x to be infinite", uint64(value))
+		}
+	}
+	for _, value := range []Nanotime{PositiveInfinityNanotime, NegativeInfinityNanotime} {
+		if !value.IsInfinite() || value.IsZero() {
+			t.Errorf("Expected 0x%016x to be infinite", uint64(value))
+		}
+	}
+	if MaxSmalltime.IsInfinite() || MinNanotime.IsInfinite() {
+		t.Errorf("Expected the range limits not to be infinite")
+	}
+
+	if PositiveInfinitySmalltime.Infinity() != 1 || NegativeInfinitySmalltime.Infinity() != -1 ||
+		PositiveInfinityNanotime.Infinity() != 1 || NegativeInfinityNanotime.Infinity() != -1 {
+		t.Errorf("Expected the sentinels to report their sign")
+	}
+	if MaxSmalltime.Infinity() != 0 || MinNanotime.Infinity() != 0 || Smalltime48(0).Infinity() != 0 {
+		t.Errorf("Expected other values to report no infinity")
+	}
+}
+
+func TestFormatParseInfinity(t *testing.T) {
+	assertFormat(t, PositiveInfinitySmalltime, "infinity")
+	assertFormat(t, NegativeInfinitySmalltime, "-infinity")
+	assertFormat(t, PositiveInfinityNanotime, "infinity")
+	assertFormat(t, NegativeInfinityNanotime, "-infinity")
+
+	assertParseSmalltime(t, "infinity", PositiveInfinitySmalltime)
+	assertParseSmalltime(t, "+Infinity", PositiveInfinitySmalltime)
+	assertParseSmalltime(t, "-INFINITY", NegativeInfinitySmalltime)
+	assertParseNanotime(t, "infinity", PositiveInfinityNanotime)
+	assertParseNanotime(t, "-infinity", NegativeInfinityNanotime)
+	assertParseFails(t, "infinit")
+	assertParseFails(t, "--infinity")
+}
+
+type sentinelRecord struct {
+	Small Smalltime `json:"small"`
+	Nano  Nanotime  `json:"nano"`
+}
+
+type sentinelTextRecord struct {
+	Small SmalltimeText `json:"small"`
+	Nano  NanotimeText  `json:"nano"`
+}
+
+func assertJSONRoundTrip[T comparable](t *testing.T, record T, expected string) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Errorf("Unexpected error marshaling %+v: %v", record, err)
+		return
+	}
+	if string(data) != expected {
+		t.Errorf("Expected %v but got %v", expected, string(data))
+	}
+	var decoded T
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Unexpected error unmarshaling %v: %v", string(data), err)
+		return
+	}
+	if decoded != record {
+		t.Errorf("Expected %v to unmarshal to %+v but got %+v", string(data), record, decoded)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	assertJSONRoundTrip(t, sentinelRecord{}, `{"small":null,"nano":null}`)
+	assertJSONRoundTrip(t, sentinelRecord{PositiveInfinitySmalltime, NegativeInfinityNanotime}, `{"small":9223372036854775807,"nano":1}`)
+	assertJSONRoundTrip(t, sentinelRecord{NegativeInfinitySmalltime, PositiveInfinityNanotime}, `{"small":-9223372036854775808,"nano":18446744073709551615}`)
+	assertJSONRoundTrip(t,
+		sentinelRecord{NewSmalltime(1999, 2, 15, 12, 8, 45, 9122), NewNanotime(2016, 12, 31, 23, 59, 60, 5)},
+		`{"small":140678029412148130,"nano":3373160657355538437}`)
+
+	// The text form is accepted too, so that either side can switch to it first
+	var record sentinelRecord
+	if err := json.Unmarshal([]byte(`{"small":"1999-02-15T12:08:45.009122Z","nano":"infinity"}`), &record); err != nil ||
+		record.Small != NewSmalltime(1999, 2, 15, 12, 8, 45, 9122) || record.Nano != PositiveInfinityNanotime {
+		t.Errorf("Unexpected result %+v (error %v)", record, err)
+	}
+	var value Smalltime
+	for _, data := range []string{`1.5`, `true`, `"bogus"`, `99999999999999999999`} {
+		if err := json.Unmarshal([]byte(data), &value); err == nil {
+			t.Errorf("Expected unmarshaling %v to fail", data)
+		}
+	}
+	var nano Nanotime
+	if err := json.Unmarshal([]byte(`-1`), &nano); err == nil {
+		t.Errorf("Expected unmarshaling a negative nanotime to fail")
+	}
+}
+
+// Data written before the text forms were added stores the raw integer.
+func TestJSONDecodesRawIntegers(t *testing.T) {
+	var record struct{ T Smalltime }
+	if err := json.Unmarshal([]byte(`{"T":140678029412148130}`), &record); err != nil || record.T != NewSmalltime(1999, 2, 15, 12, 8, 45, 9122) {
+		t.Errorf("Unexpected result %v (error %v)", Format(record.T), err)
+	}
+	var text struct{ T SmalltimeText }
+	if err := json.Unmarshal([]byte(`{"T":140678029412148130}`), &text); err != nil || Smalltime(text.T) != record.T {
+		t.Errorf("Unexpected result %v (error %v)", Format(Smalltime(text.T)), err)
+	}
+}
+
+func TestJSONText(t *testing.T) {
+	assertJSONRoundTrip(t, sentinelTextRecord{}, `{"small":null,"nano":null}`)
+	assertJSONRoundTrip(t, sentinelTextRecord{SmalltimeText(PositiveInfinitySmalltime), NanotimeText(NegativeInfinityNanotime)}, `{"small":"infinity","nano":"-infinity"}`)
+	assertJSONRoundTrip(t, sentinelTextRecord{SmalltimeText(NegativeInfinitySmalltime), NanotimeText(PositiveInfinityNanotime)}, `{"small":"-infinity","nano":"infinity"}`)
+	assertJSONRoundTrip(t,
+		sentinelTextRecord{SmalltimeText(NewSmalltime(1999, 2, 15, 12, 8, 45, 9122)), NanotimeText(NewNanotime(2016, 12, 31, 23, 59, 60, 5))},
+		`{"small":"1999-02-15T12:08:45.009122Z","nano":"2016-12-31T23:59:60.000000005Z"}`)
+
+	if _, err := json.Marshal(SmalltimeText(NewSmalltime(2001, 2, 29, 0, 0, 0, 0))); err == nil {
+		t.Errorf("Expected marshaling an invalid date to fail")
+	}
+	var value SmalltimeText
+	if err := json.Unmarshal([]byte(`""`), &value); err != nil || value != 0 {
+		t.Errorf("Expected an empty string to unmarshal to zero (error %v)", err)
+	}
+	if text, err := NanotimeText(0).MarshalText(); err != nil || len(text) != 0 {
+		t.Errorf("Expected zero to marshal to an empty string but got %q (error %v)", text, err)
+	}
+}
+
+func TestSQLSmalltime(t *testing.T) {
+	values := map[Smalltime]driver.Value{
+		0:                                    nil,
+		PositiveInfinitySmalltime:            int64(math.MaxInt64),
+		NegativeInfinitySmalltime:            int64(math.MinInt64),
+		NewSmalltime(-44, 3, 15, 0, 0, 0, 0): int64(NewSmalltime(-44, 3, 15, 0, 0, 0, 0)),
+	}
+	for value, expected := range values {
+		actual, err := value.Value()
+		if err != nil || actual != expected {
+			t.Errorf("Expected 0x%016x to give %v but got %v (error %v)", uint64(value), expected, actual, err)
+		}
+		var scanned Smalltime = 1
+		if err = scanned.Scan(actual); err != nil || scanned != value {
+			t.Errorf("Expected %v to scan as 0x%016x but got 0x%016x (error %v)", actual, uint64(value), uint64(scanned), err)
+		}
+	}
+
+	var scanned Smalltime
+	expected := NewSmalltime(1999, 2, 15, 12, 8, 45, 9122)
+	sources := []interface{}{
+		time.Date(1999, 2, 15, 13, 8, 45, 9122000, time.FixedZone("", 3600)),
+		[]byte("1999-02-15T12:08:45.009122Z"),
+		int64(expected),
+	}
+	for _, src := range sources {
+		if err := scanned.Scan(src); err != nil || scanned != expected {
+			t.Errorf("Expected %v to scan as %v but got %v (error %v)", src, Format(expected), Format(scanned), err)
+		}
+	}
+	if err := scanned.Scan(1.5); err == nil {
+		t.Errorf("Expected scanning a float to fail")
+	}
+}
+
+func TestSQLSmalltimeText(t *testing.T) {
+	values := map[SmalltimeText]driver.Value{
+		0:                                        nil,
+		SmalltimeText(PositiveInfinitySmalltime): "infinity",
+		SmalltimeText(NegativeInfinitySmalltime): "-infinity",
+		SmalltimeText(NewSmalltime(-44, 3, 15, 0, 0, 0, 0)): "-0044-03-15T00:00:00Z",
+	}
+	for value, expected := range values {
+		actual, err := value.Value()
+		if err != nil || actual != expected {
+			t.Errorf("Expected 0x%016x to give %v but got %v (error %v)", uint64(value), expected, actual, err)
+		}
+		var scanned SmalltimeText = 1
+		if err = scanned.Scan(actual); err != nil || scanned != value {
+			t.Errorf("Expected %v to scan as 0x%016x but got 0x%016x (error %v)", actual, uint64(value), uint64(scanned), err)
+		}
+	}
+}
+
+func TestSQLNanotime(t *testing.T) {
+	values := map[Nanotime]driver.Value{
+		0:                        nil,
+		PositiveInfinityNanotime: int64(-1),
+		NegativeInfinityNanotime: int64(1),
+		MinNanotime:              int64(MinNanotime),
+		MaxNanotime:              int64(-13546262500816385),
+	}
+	for value, expected := range values {
+		actual, err := value.Value()
+		if err != nil || actual != expected {
+			t.Errorf("Expected 0x%016x to give %v but got %v (error %v)", uint64(value), expected, actual, err)
+		}
+		var scanned Nanotime = 2
+		if err = scanned.Scan(actual); err != nil || scanned != value {
+			t.Errorf("Expected %v to scan as 0x%016x but got 0x%016x (error %v)", actual, uint64(value), uint64(scanned), err)
+		}
+	}
+
+	var scanned Nanotime
+	if err := scanned.Scan(time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("Expected scanning a time before 1970 to fail")
+	}
+	if err := scanned.Scan("2016-12-31T23:59:60Z"); err != nil || scanned != NewNanotime(2016, 12, 31, 23, 59, 60, 0) {
+		t.Errorf("Unexpected result %v (error %v)", Format(scanned), err)
+	}
+}
+
+func TestSQLNanotimeText(t *testing.T) {
+	values := map[NanotimeText]driver.Value{
+		0:                                      nil,
+		NanotimeText(PositiveInfinityNanotime): "infinity",
+		NanotimeText(NegativeInfinityNanotime): "-infinity",
+		NanotimeText(MinNanotime):              "1970-01-01T00:00:00Z",
+	}
+	for value, expected := range values {
+		actual, err := value.Value()
+		if err != nil || actual != expected {
+			t.Errorf("Expected 0x%016x to give %v but got %v (error %v)", uint64(value), expected, actual, err)
+		}
+		var scanned NanotimeText = 2
+		if err = scanned.Scan(actual); err != nil || scanned != value {
+			t.Errorf("Expected %v to scan as 0x%016x but got 0x%016x (error %v)", actual, uint64(value), uint64(scanned), err)
+		}
+	}
+}
@@ -19,12 +19,49 @@ const maskMinute = Smalltime(0x3f) << bitshiftMinute
 const maskSecond = Smalltime(0x3f) << bitshiftSecond
 const maskMicrosecond = Smalltime(0xfffff)
 
+// Years use astronomical numbering on the proleptic Gregorian calendar: year 0
+// is 1 BC, year -1 is 2 BC, and the Gregorian leap year rules apply to every
+// year (so 0, -4 and -400 are leap years, but -100 is not). Because the year
+// occupies the most significant bits of a signed integer, values before year 0
+// still compare correctly.
+const minYearSmalltime = -131072
+const maxYearSmalltime = 131071
+
+// MinSmalltime is the earliest representable date & time: -131072-01-01T00:00:00Z
+const MinSmalltime = Smalltime(minYearSmalltime)<<bitshiftYear | 1<<bitshiftMonth | 1<<bitshiftDay
+
+// MaxSmalltime is the latest representable date & time: +131071-12-31T23:59:59.999999Z.
+// It isn't a leap second, so that it still converts to a time.Time in range.
+const MaxSmalltime = Smalltime(maxYearSmalltime)<<bitshiftYear | 12<<bitshiftMonth | 31<<bitshiftDay |
+	23<<bitshiftHour | 59<<bitshiftMinute | 59<<bitshiftSecond | 999999
+
 func SmalltimeFromTime(t time.Time) Smalltime {
 	t = t.UTC()
 	return NewSmalltime(t.Year(), int(t.Month()), t.Day(), t.Hour(),
 		t.Minute(), t.Second(), t.Nanosecond()/1000)
 }
 
+// SmalltimeFromTimeChecked converts t, returning a RangeError (matching
+// ErrOutOfRange) if its year is outside of [MinSmalltime, MaxSmalltime].
+func SmalltimeFromTimeChecked(t time.Time) (Smalltime, error) {
+	if err := checkRange("year", t.UTC().Year(), minYearSmalltime, maxYearSmalltime); err != nil {
+		return 0, err
+	}
+	return SmalltimeFromTime(t), nil
+}
+
+// SmalltimeFromTimeSaturating converts t, clamping it to
+// [MinSmalltime, MaxSmalltime].
+func SmalltimeFromTimeSaturating(t time.Time) Smalltime {
+	switch year := t.UTC().Year(); {
+	case year < minYearSmalltime:
+		return MinSmalltime
+	case year > maxYearSmalltime:
+		return MaxSmalltime
+	}
+	return SmalltimeFromTime(t)
+}
+
 func NewSmalltime(year, month, day, hour, minute, second, microsecond int) Smalltime {
 	return Smalltime(year)<<bitshiftYear |
 		Smalltime(month)<<bitshiftMonth |
@@ -80,3 +117,112 @@ func (time Smalltime) Second() int {
 func (time Smalltime) Microsecond() int {
 	return int(time & maskMicrosecond)
 }
+
+// Validate returns a RangeError for the first field that is out of range (for
+// example, a day that doesn't exist in the month), or nil if all are valid.
+func (time Smalltime) Validate() error {
+	return validateFields(time.Year(), time.Month(), time.Day(), time.Hour(), time.Minute(),
+		time.Second(), time.Microsecond(), 999999, "microsecond")
+}
+
+func (time Smalltime) Millisecond() int {
+	return time.Microsecond() / 1000
+}
+
+func (time Smalltime) Nanosecond() int {
+	return time.Microsecond() * 1000
+}
+
+func (time Smalltime) SubsecondNanos() int {
+	return time.Microsecond() * 1000
+}
+
+// Fraction returns the sub-second portion truncated to the specified number of
+// decimal digits (0-9), for fixed-point printing.
+func (time Smalltime) Fraction(digits int) int {
+	return fraction(time.SubsecondNanos(), digits)
+}
+
+func (time Smalltime) WithMillisecond(millisecond int) (Smalltime, error) {
+	if err := checkRange("millisecond", millisecond, 0, 999); err != nil {
+		return time, err
+	}
+	return time.withMicrosecond(millisecond * 1000), nil
+}
+
+func (time Smalltime) WithMicrosecond(microsecond int) (Smalltime, error) {
+	if err := checkRange("microsecond", microsecond, 0, 999999); err != nil {
+		return time, err
+	}
+	return time.withMicrosecond(microsecond), nil
+}
+
+// WithNanosecond sets the sub-second portion, truncated to the microsecond.
+func (time Smalltime) WithNanosecond(nanosecond int) (Smalltime, error) {
+	if err := checkRange("nanosecond", nanosecond, 0, 999999999); err != nil {
+		return time, err
+	}
+	return time.withMicrosecond(nanosecond / 1000), nil
+}
+
+func (time Smalltime) withMicrosecond(microsecond int) Smalltime {
+	return time&^maskMicrosecond | Smalltime(microsecond)
+}
+
+func (time Smalltime) WithYear(year int, policy DayPolicy) (Smalltime, error) {
+	if err := checkRange("year", year, minYearSmalltime, maxYearSmalltime); err != nil {
+		return time, err
+	}
+	day, err := applyDayPolicy(policy, year, time.Month(), time.Day())
+	if err != nil {
+		return time, err
+	}
+	time = time&^maskDay | Smalltime(day)<<bitshiftDay
+	return time&(1<<bitshiftYear-1) | Smalltime(year)<<bitshiftYear, nil
+}
+
+func (time Smalltime) WithMonth(month int, policy DayPolicy) (Smalltime, error) {
+	if err := checkRange("month", month, 1, 12); err != nil {
+		return time, err
+	}
+	day, err := applyDayPolicy(policy, time.Year(), month, time.Day())
+	if err != nil {
+		return time, err
+	}
+	time = time&^maskDay | Smalltime(day)<<bitshiftDay
+	return time&^maskMonth | Smalltime(month)<<bitshiftMonth, nil
+}
+
+// WithDay sets the day of month, which must exist in the current month.
+func (time Smalltime) WithDay(day int) (Smalltime, error) {
+	max := 31
+	if month := time.Month(); month >= 1 && month <= 12 {
+		max = daysInMonth(time.Year(), month)
+	}
+	if err := checkRange("day", day, 1, max); err != nil {
+		return time, err
+	}
+	return time&^maskDay | Smalltime(day)<<bitshiftDay, nil
+}
+
+func (time Smalltime) WithHour(hour int) (Smalltime, error) {
+	if err := checkRange("hour", hour, 0, 23); err != nil {
+		return time, err
+	}
+	return time&^maskHour | Smalltime(hour)<<bitshiftHour, nil
+}
+
+func (time Smalltime) WithMinute(minute int) (Smalltime, error) {
+	if err := checkRange("minute", minute, 0, 59); err != nil {
+		return time, err
+	}
+	return time&^maskMinute | Smalltime(minute)<<bitshiftMinute, nil
+}
+
+// WithSecond sets the second, allowing 60 for leap seconds.
+func (time Smalltime) WithSecond(second int) (Smalltime, error) {
+	if err := checkRange("second", second, 0, 60); err != nil {
+		return time, err
+	}
+	return time&^maskSecond | Smalltime(second)<<bitshiftSecond, nil
+}
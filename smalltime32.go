@@ -0,0 +1,105 @@
+package smalltime
+
+import "time"
+
+// Smalltime32 is a compact 32-bit, second-resolution variant of Smalltime.
+// The year is stored as an unsigned 6-bit offset from an epoch year, so the
+// same epoch must be used when encoding and decoding. The representable range
+// is epochYear-01-01T00:00:00 to (epochYear+63)-12-31T23:59:60.
+type Smalltime32 uint32
+
+const DefaultEpochSmalltime32 = 2000
+
+const bitshiftYearSmalltime32 = 26
+const bitshiftMonthSmalltime32 = 22
+const bitshiftDaySmalltime32 = 17
+const bitshiftHourSmalltime32 = 12
+const bitshiftMinuteSmalltime32 = 6
+
+const maskYearSmalltime32 = Smalltime32(0x3f) << bitshiftYearSmalltime32
+const maskMonthSmalltime32 = Smalltime32(0xf) << bitshiftMonthSmalltime32
+const maskDaySmalltime32 = Smalltime32(0x1f) << bitshiftDaySmalltime32
+const maskHourSmalltime32 = Smalltime32(0x1f) << bitshiftHourSmalltime32
+const maskMinuteSmalltime32 = Smalltime32(0x3f) << bitshiftMinuteSmalltime32
+const maskSecondSmalltime32 = Smalltime32(0x3f)
+
+func Smalltime32FromTime(epochYear int, t time.Time) Smalltime32 {
+	t = t.UTC()
+	return NewSmalltime32(epochYear, t.Year(), int(t.Month()), t.Day(), t.Hour(),
+		t.Minute(), t.Second())
+}
+
+// Convert a Smalltime to a Smalltime32, discarding the microseconds.
+func Smalltime32FromSmalltime(epochYear int, t Smalltime) Smalltime32 {
+	return NewSmalltime32(epochYear, t.Year(), t.Month(), t.Day(), t.Hour(),
+		t.Minute(), t.Second())
+}
+
+// Smalltime32FromSmalltimeChecked converts t, returning a RangeError
+// (matching ErrOutOfRange) if its year is outside of
+// [epochYear, epochYear+63].
+func Smalltime32FromSmalltimeChecked(epochYear int, t Smalltime) (Smalltime32, error) {
+	if err := checkRange("year", t.Year(), epochYear, epochYear+63); err != nil {
+		return 0, err
+	}
+	return Smalltime32FromSmalltime(epochYear, t), nil
+}
+
+// NewSmalltime32 encodes the fields as-is, without validating them. A year
+// outside of [epochYear, epochYear+63] is truncated to its low 6 bits of
+// offset, wrapping around into the range.
+func NewSmalltime32(epochYear, year, month, day, hour, minute, second int) Smalltime32 {
+	return (Smalltime32(year-epochYear)<<bitshiftYearSmalltime32)&maskYearSmalltime32 |
+		Smalltime32(month)<<bitshiftMonthSmalltime32 |
+		Smalltime32(day)<<bitshiftDaySmalltime32 |
+		Smalltime32(hour)<<bitshiftHourSmalltime32 |
+		Smalltime32(minute)<<bitshiftMinuteSmalltime32 |
+		Smalltime32(second)
+}
+
+func NewSmalltime32WithDoy(epochYear, year, dayOfYear, hour, minute, second int) Smalltime32 {
+	month, day := doyToYmd(year, dayOfYear)
+	return NewSmalltime32(epochYear, year, month, day, hour, minute, second)
+}
+
+func (t Smalltime32) AsTime(epochYear int) time.Time {
+	return t.AsTimeInLocation(epochYear, time.UTC)
+}
+
+func (t Smalltime32) AsTimeInLocation(epochYear int, loc *time.Location) time.Time {
+	return time.Date(t.Year(epochYear), time.Month(t.Month()), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), 0, loc)
+}
+
+func (t Smalltime32) AsSmalltime(epochYear int) Smalltime {
+	return NewSmalltime(t.Year(epochYear), t.Month(), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), 0)
+}
+
+func (time Smalltime32) Year(epochYear int) int {
+	return int(time>>bitshiftYearSmalltime32) + epochYear
+}
+
+func (time Smalltime32) Doy(epochYear int) int {
+	return ymdToDoy(time.Year(epochYear), time.Month(), time.Day())
+}
+
+func (time Smalltime32) Month() int {
+	return int((time & maskMonthSmalltime32) >> bitshiftMonthSmalltime32)
+}
+
+func (time Smalltime32) Day() int {
+	return int((time & maskDaySmalltime32) >> bitshiftDaySmalltime32)
+}
+
+func (time Smalltime32) Hour() int {
+	return int((time & maskHourSmalltime32) >> bitshiftHourSmalltime32)
+}
+
+func (time Smalltime32) Minute() int {
+	return int((time & maskMinuteSmalltime32) >> bitshiftMinuteSmalltime32)
+}
+
+func (time Smalltime32) Second() int {
+	return int(time & maskSecondSmalltime32)
+}
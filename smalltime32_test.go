@@ -0,0 +1,122 @@
+package smalltime
+
+import "errors"
+import "testing"
+import "time"
+
+func assertEncodeDecodeSmalltime32(t *testing.T, epochYear, year, month, day, hour, minute, second int) {
+	time := NewSmalltime32(epochYear, year, month, day, hour, minute, second)
+	if time.Year(epochYear) != year || time.Month() != month || time.Day() != day ||
+		time.Hour() != hour || time.Minute() != minute || time.Second() != second {
+		t.Errorf("Expected: %04d-%02d-%02dT%02d:%02d:%02d, Actual: %04d-%02d-%02dT%02d:%02d:%02d (doy %d)",
+			year, month, day, hour, minute, second,
+			time.Year(epochYear), time.Month(), time.Day(), time.Hour(), time.Minute(), time.Second(), time.Doy(epochYear))
+	}
+}
+
+func assertSmalltime32GotimeEquivalence(t *testing.T, epochYear, year, month, day, hour, minute, second int) {
+	smtime := NewSmalltime32(epochYear, year, month, day, hour, minute, second)
+	gotime := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+
+	if smtime.AsTime(epochYear) != gotime {
+		t.Errorf("%04d-%02d-%02dT%02d:%02d:%02d did not convert cleanly to %v",
+			smtime.Year(epochYear), smtime.Month(), smtime.Day(), smtime.Hour(),
+			smtime.Minute(), smtime.Second(), gotime)
+	}
+
+	if Smalltime32FromTime(epochYear, gotime) != smtime {
+		t.Errorf("%v did not convert cleanly to %04d-%02d-%02dT%02d:%02d:%02d",
+			gotime, smtime.Year(epochYear), smtime.Month(), smtime.Day(), smtime.Hour(),
+			smtime.Minute(), smtime.Second())
+	}
+}
+
+func assertSmalltime32Greater(t *testing.T, greater, smaller Smalltime32) {
+	if greater <= smaller {
+		t.Errorf("%08x is not greater than %08x", greater, smaller)
+	}
+}
+
+func TestRangeSmalltime32(t *testing.T) {
+	for _, epochYear := range []int{1970, DefaultEpochSmalltime32, 2020} {
+		for year := epochYear; year < epochYear+64; year++ {
+			assertEncodeDecodeSmalltime32(t, epochYear, year, 1, 1, 0, 0, 0)
+			assertEncodeDecodeSmalltime32(t, epochYear, year, 12, 31, 23, 59, 60)
+			assertSmalltime32GotimeEquivalence(t, epochYear, year, 6, 15, 12, 30, 45)
+		}
+	}
+}
+
+func TestOutOfRangeSmalltime32(t *testing.T) {
+	epochYear := DefaultEpochSmalltime32
+	for _, year := range []int{1999, 2064, -5000} {
+		wrapped := NewSmalltime32(epochYear, year, 6, 15, 12, 30, 45)
+		if expected := epochYear + ((year-epochYear)%64+64)%64; wrapped.Year(epochYear) != expected {
+			t.Errorf("Expected year %v to wrap to %v but got %v", year, expected, wrapped.Year(epochYear))
+		}
+		if wrapped.Month() != 6 || wrapped.Second() != 45 {
+			t.Errorf("Expected year %v not to disturb the other fields", year)
+		}
+		if _, err := Smalltime32FromSmalltimeChecked(epochYear, NewSmalltime(year, 6, 15, 12, 30, 45, 0)); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected year %v to give ErrOutOfRange but got %v", year, err)
+		}
+	}
+	value, err := Smalltime32FromSmalltimeChecked(epochYear, NewSmalltime(2063, 12, 31, 23, 59, 60, 0))
+	if err != nil || value != NewSmalltime32(epochYear, 2063, 12, 31, 23, 59, 60) {
+		t.Errorf("Unexpected result %v (error %v)", value, err)
+	}
+}
+
+func TestFieldsSmalltime32(t *testing.T) {
+	epochYear := DefaultEpochSmalltime32
+	for month := 1; month <= 12; month++ {
+		assertSmalltime32GotimeEquivalence(t, epochYear, 2004, month, 15, 8, 30, 55)
+	}
+	for day := 1; day <= 31; day++ {
+		assertSmalltime32GotimeEquivalence(t, epochYear, 2004, 1, day, 8, 30, 55)
+	}
+	for hour := 0; hour < 24; hour++ {
+		assertSmalltime32GotimeEquivalence(t, epochYear, 2004, 1, 15, hour, 30, 55)
+	}
+	for minute := 0; minute < 60; minute++ {
+		assertSmalltime32GotimeEquivalence(t, epochYear, 2004, 1, 15, 8, minute, 55)
+	}
+	for second := 0; second < 60; second++ {
+		assertSmalltime32GotimeEquivalence(t, epochYear, 2004, 1, 15, 8, 30, second)
+	}
+}
+
+func TestDoySmalltime32(t *testing.T) {
+	epochYear := DefaultEpochSmalltime32
+	time := NewSmalltime32WithDoy(epochYear, 2000, 60, 1, 2, 3)
+	if time != NewSmalltime32(epochYear, 2000, 2, 29, 1, 2, 3) {
+		t.Errorf("Expected doy 60 of 2000 to be Feb 29, but got month %v day %v", time.Month(), time.Day())
+	}
+	if time.Doy(epochYear) != 60 {
+		t.Errorf("Expected doy 60 but got %v", time.Doy(epochYear))
+	}
+}
+
+func TestSmalltimeConversionSmalltime32(t *testing.T) {
+	epochYear := DefaultEpochSmalltime32
+	smtime := NewSmalltime(2019, 5, 20, 14, 22, 60, 123456)
+	compact := Smalltime32FromSmalltime(epochYear, smtime)
+	if compact != NewSmalltime32(epochYear, 2019, 5, 20, 14, 22, 60) {
+		t.Errorf("Expected %016x to convert to %08x but got %08x", smtime,
+			NewSmalltime32(epochYear, 2019, 5, 20, 14, 22, 60), compact)
+	}
+	expected := NewSmalltime(2019, 5, 20, 14, 22, 60, 0)
+	if compact.AsSmalltime(epochYear) != expected {
+		t.Errorf("Expected %08x to convert to %016x but got %016x", compact, expected, compact.AsSmalltime(epochYear))
+	}
+}
+
+func TestComparisonsSmalltime32(t *testing.T) {
+	epochYear := DefaultEpochSmalltime32
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2000, 1, 1, 0, 0, 1), NewSmalltime32(epochYear, 2000, 1, 1, 0, 0, 0))
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2000, 1, 1, 0, 1, 0), NewSmalltime32(epochYear, 2000, 1, 1, 0, 0, 60))
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2000, 1, 1, 1, 0, 0), NewSmalltime32(epochYear, 2000, 1, 1, 0, 59, 0))
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2000, 1, 2, 0, 0, 0), NewSmalltime32(epochYear, 2000, 1, 1, 23, 0, 0))
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2000, 2, 1, 0, 0, 0), NewSmalltime32(epochYear, 2000, 1, 31, 0, 0, 0))
+	assertSmalltime32Greater(t, NewSmalltime32(epochYear, 2063, 1, 1, 0, 0, 0), NewSmalltime32(epochYear, 2062, 12, 31, 0, 0, 0))
+}
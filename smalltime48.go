@@ -0,0 +1,118 @@
+package smalltime
+
+import "time"
+
+// Smalltime48 is a compact 48-bit, millisecond-resolution variant of
+// Smalltime, stored in the low 48 bits of a uint64. The year is stored as an
+// unsigned 12-bit value, giving a range of 0000-01-01T00:00:00.000 to
+// 4095-12-31T23:59:60.999.
+type Smalltime48 uint64
+
+const bitshiftYearSmalltime48 = 36
+const bitshiftMonthSmalltime48 = 32
+const bitshiftDaySmalltime48 = 27
+const bitshiftHourSmalltime48 = 22
+const bitshiftMinuteSmalltime48 = 16
+const bitshiftSecondSmalltime48 = 10
+
+const maskYearSmalltime48 = Smalltime48(0xfff) << bitshiftYearSmalltime48
+const maskMonthSmalltime48 = Smalltime48(0xf) << bitshiftMonthSmalltime48
+const maskDaySmalltime48 = Smalltime48(0x1f) << bitshiftDaySmalltime48
+const maskHourSmalltime48 = Smalltime48(0x1f) << bitshiftHourSmalltime48
+const maskMinuteSmalltime48 = Smalltime48(0x3f) << bitshiftMinuteSmalltime48
+const maskSecondSmalltime48 = Smalltime48(0x3f) << bitshiftSecondSmalltime48
+const maskMillisecondSmalltime48 = Smalltime48(0x3ff)
+
+func Smalltime48FromTime(t time.Time) Smalltime48 {
+	t = t.UTC()
+	return NewSmalltime48(t.Year(), int(t.Month()), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), t.Nanosecond()/1000000)
+}
+
+// Convert a Smalltime to a Smalltime48, truncating to the millisecond.
+func Smalltime48FromSmalltime(t Smalltime) Smalltime48 {
+	return NewSmalltime48(t.Year(), t.Month(), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), t.Microsecond()/1000)
+}
+
+// Smalltime48FromSmalltimeChecked converts t, returning a RangeError
+// (matching ErrOutOfRange) if its year is outside of [0, 4095].
+func Smalltime48FromSmalltimeChecked(t Smalltime) (Smalltime48, error) {
+	if err := checkRange("year", t.Year(), 0, 4095); err != nil {
+		return 0, err
+	}
+	return Smalltime48FromSmalltime(t), nil
+}
+
+// NewSmalltime48 encodes the fields as-is, without validating them. A year
+// outside of [0, 4095] is truncated to its low 12 bits, wrapping around into
+// the range.
+func NewSmalltime48(year, month, day, hour, minute, second, millisecond int) Smalltime48 {
+	return (Smalltime48(year)<<bitshiftYearSmalltime48)&maskYearSmalltime48 |
+		Smalltime48(month)<<bitshiftMonthSmalltime48 |
+		Smalltime48(day)<<bitshiftDaySmalltime48 |
+		Smalltime48(hour)<<bitshiftHourSmalltime48 |
+		Smalltime48(minute)<<bitshiftMinuteSmalltime48 |
+		Smalltime48(second)<<bitshiftSecondSmalltime48 |
+		Smalltime48(millisecond)
+}
+
+func NewSmalltime48WithDoy(year, dayOfYear, hour, minute, second, millisecond int) Smalltime48 {
+	month, day := doyToYmd(year, dayOfYear)
+	return NewSmalltime48(year, month, day, hour, minute, second, millisecond)
+}
+
+// Smalltime48 has no infinity sentinels, so Infinity always returns 0.
+func (time Smalltime48) Infinity() int {
+	return 0
+}
+
+func (t Smalltime48) AsTime() time.Time {
+	return t.AsTimeInLocation(time.UTC)
+}
+
+func (t Smalltime48) AsTimeInLocation(loc *time.Location) time.Time {
+	return time.Date(t.Year(), time.Month(t.Month()), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), t.Millisecond()*1000000, loc)
+}
+
+func (t Smalltime48) AsSmalltime() Smalltime {
+	return NewSmalltime(t.Year(), t.Month(), t.Day(), t.Hour(),
+		t.Minute(), t.Second(), t.Millisecond()*1000)
+}
+
+func (time Smalltime48) Year() int {
+	return int((time & maskYearSmalltime48) >> bitshiftYearSmalltime48)
+}
+
+func (time Smalltime48) Doy() int {
+	return ymdToDoy(time.Year(), time.Month(), time.Day())
+}
+
+func (time Smalltime48) Month() int {
+	return int((time & maskMonthSmalltime48) >> bitshiftMonthSmalltime48)
+}
+
+func (time Smalltime48) Day() int {
+	return int((time & maskDaySmalltime48) >> bitshiftDaySmalltime48)
+}
+
+func (time Smalltime48) Hour() int {
+	return int((time & maskHourSmalltime48) >> bitshiftHourSmalltime48)
+}
+
+func (time Smalltime48) Minute() int {
+	return int((time & maskMinuteSmalltime48) >> bitshiftMinuteSmalltime48)
+}
+
+func (time Smalltime48) Second() int {
+	return int((time & maskSecondSmalltime48) >> bitshiftSecondSmalltime48)
+}
+
+func (time Smalltime48) Millisecond() int {
+	return int(time & maskMillisecondSmalltime48)
+}
+
+func (time Smalltime48) SubsecondNanos() int {
+	return time.Millisecond() * 1000000
+}
@@ -0,0 +1,114 @@
+package smalltime
+
+import "errors"
+import "testing"
+import "time"
+
+func assertEncodeDecodeSmalltime48(t *testing.T, year, month, day, hour, minute, second, msec int) {
+	time := NewSmalltime48(year, month, day, hour, minute, second, msec)
+	if time.Year() != year || time.Month() != month || time.Day() != day ||
+		time.Hour() != hour || time.Minute() != minute || time.Second() != second || time.Millisecond() != msec {
+		t.Errorf("Expected: %04d-%02d-%02dT%02d:%02d:%02d.%03d, Actual: %04d-%02d-%02dT%02d:%02d:%02d.%03d (doy %d)",
+			year, month, day, hour, minute, second, msec,
+			time.Year(), time.Month(), time.Day(), time.Hour(), time.Minute(), time.Second(), time.Millisecond(), time.Doy())
+	}
+}
+
+func assertSmalltime48GotimeEquivalence(t *testing.T, year, month, day, hour, minute, second, msec int) {
+	smtime := NewSmalltime48(year, month, day, hour, minute, second, msec)
+	gotime := time.Date(year, time.Month(month), day, hour, minute, second, msec*1000000, time.UTC)
+
+	if smtime.AsTime() != gotime {
+		t.Errorf("%04d-%02d-%02dT%02d:%02d:%02d.%03d did not convert cleanly to %v",
+			smtime.Year(), smtime.Month(), smtime.Day(), smtime.Hour(),
+			smtime.Minute(), smtime.Second(), smtime.Millisecond(), gotime)
+	}
+
+	if Smalltime48FromTime(gotime) != smtime {
+		t.Errorf("%v did not convert cleanly to %04d-%02d-%02dT%02d:%02d:%02d.%03d",
+			gotime, smtime.Year(), smtime.Month(), smtime.Day(), smtime.Hour(),
+			smtime.Minute(), smtime.Second(), smtime.Millisecond())
+	}
+}
+
+func assertSmalltime48Greater(t *testing.T, greater, smaller Smalltime48) {
+	if greater <= smaller {
+		t.Errorf("%012x is not greater than %012x", greater, smaller)
+	}
+}
+
+func TestRangeSmalltime48(t *testing.T) {
+	for year := 0; year < 4096; year++ {
+		assertEncodeDecodeSmalltime48(t, year, 1, 1, 0, 0, 0, 0)
+		assertEncodeDecodeSmalltime48(t, year, 12, 31, 23, 59, 60, 999)
+		assertSmalltime48GotimeEquivalence(t, year, 6, 15, 12, 30, 45, 500)
+	}
+	max := NewSmalltime48(4095, 12, 31, 23, 59, 60, 999)
+	if max>>48 != 0 {
+		t.Errorf("Expected %016x to fit in 48 bits", max)
+	}
+}
+
+func TestOutOfRangeSmalltime48(t *testing.T) {
+	for _, year := range []int{-1, 4096, 131071} {
+		wrapped := NewSmalltime48(year, 6, 15, 12, 30, 45, 500)
+		if expected := year & 0xfff; wrapped.Year() != expected {
+			t.Errorf("Expected year %v to wrap to %v but got %v", year, expected, wrapped.Year())
+		}
+		if wrapped.Month() != 6 || wrapped.Millisecond() != 500 {
+			t.Errorf("Expected year %v not to disturb the other fields", year)
+		}
+		if _, err := Smalltime48FromSmalltimeChecked(NewSmalltime(year, 6, 15, 12, 30, 45, 500000)); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected year %v to give ErrOutOfRange but got %v", year, err)
+		}
+	}
+	value, err := Smalltime48FromSmalltimeChecked(NewSmalltime(4095, 12, 31, 23, 59, 60, 999999))
+	if err != nil || value != NewSmalltime48(4095, 12, 31, 23, 59, 60, 999) {
+		t.Errorf("Unexpected result %v (error %v)", value, err)
+	}
+}
+
+func TestFieldsSmalltime48(t *testing.T) {
+	for month := 1; month <= 12; month++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, month, 15, 8, 30, 55, 140)
+	}
+	for day := 1; day <= 31; day++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, 1, day, 8, 30, 55, 140)
+	}
+	for hour := 0; hour < 24; hour++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, 1, 15, hour, 30, 55, 140)
+	}
+	for minute := 0; minute < 60; minute++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, 1, 15, 8, minute, 55, 140)
+	}
+	for second := 0; second < 60; second++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, 1, 15, 8, 30, second, 140)
+	}
+	for millisecond := 0; millisecond < 1000; millisecond++ {
+		assertSmalltime48GotimeEquivalence(t, 2004, 1, 15, 8, 30, 55, millisecond)
+	}
+}
+
+func TestSmalltimeConversionSmalltime48(t *testing.T) {
+	smtime := NewSmalltime(2019, 5, 20, 14, 22, 60, 123456)
+	compact := Smalltime48FromSmalltime(smtime)
+	if compact != NewSmalltime48(2019, 5, 20, 14, 22, 60, 123) {
+		t.Errorf("Expected %016x to convert to %012x but got %012x", smtime,
+			NewSmalltime48(2019, 5, 20, 14, 22, 60, 123), compact)
+	}
+	expected := NewSmalltime(2019, 5, 20, 14, 22, 60, 123000)
+	if compact.AsSmalltime() != expected {
+		t.Errorf("Expected %012x to convert to %016x but got %016x", compact, expected, compact.AsSmalltime())
+	}
+}
+
+func TestComparisonsSmalltime48(t *testing.T) {
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 1, 1, 0, 0, 0, 1), NewSmalltime48(2000, 1, 1, 0, 0, 0, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 1, 1, 0, 0, 1, 0), NewSmalltime48(2000, 1, 1, 0, 0, 0, 999))
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 1, 1, 0, 1, 0, 0), NewSmalltime48(2000, 1, 1, 0, 0, 60, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 1, 1, 1, 0, 0, 0), NewSmalltime48(2000, 1, 1, 0, 59, 0, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 1, 2, 0, 0, 0, 0), NewSmalltime48(2000, 1, 1, 23, 0, 0, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(2000, 2, 1, 0, 0, 0, 0), NewSmalltime48(2000, 1, 31, 0, 0, 0, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(1, 1, 1, 0, 0, 0, 0), NewSmalltime48(0, 1, 1, 0, 0, 0, 0))
+	assertSmalltime48Greater(t, NewSmalltime48(4095, 1, 1, 0, 0, 0, 0), NewSmalltime48(4094, 12, 31, 0, 0, 0, 0))
+}
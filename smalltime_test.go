@@ -3,6 +3,7 @@ package smalltime
 import "testing"
 import "time"
 import "fmt"
+import "errors"
 
 func workaroundUnusedImportErrorFmt() {
 	fmt.Printf("")
@@ -317,3 +318,244 @@ func TestSpecExamples(t *testing.T) {
 	assertEncode(t, 1985, 10, 27, 8, 22, 16, 900142, Smalltime(0x1f06b68590dbc2e))
 	assertEncode(t, 1985, 10, 26, 8, 21, 16, 900142, Smalltime(0x1f06b48550dbc2e))
 }
+
+func TestSubsecondAccessors(t *testing.T) {
+	time := NewSmalltime(2003, 11, 15, 8, 30, 55, 123456)
+	if time.Millisecond() != 123 || time.Microsecond() != 123456 || time.Nanosecond() != 123456000 {
+		t.Errorf("Expected 123 / 123456 / 123456000 but got %v / %v / %v",
+			time.Millisecond(), time.Microsecond(), time.Nanosecond())
+	}
+	for digits, expected := range []int{0, 1, 12, 123, 1234, 12345, 123456, 1234560, 12345600, 123456000} {
+		if actual := time.Fraction(digits); actual != expected {
+			t.Errorf("Expected Fraction(%v) to be %v but got %v", digits, expected, actual)
+		}
+	}
+	if actual := time.Fraction(10); actual != 123456000 {
+		t.Errorf("Expected Fraction(10) to clamp to 123456000 but got %v", actual)
+	}
+}
+
+func TestSubsecondSetters(t *testing.T) {
+	time := NewSmalltime(2003, 11, 15, 8, 30, 55, 123456)
+	assertWithSubsecond(t, time.WithMillisecond, 999, NewSmalltime(2003, 11, 15, 8, 30, 55, 999000))
+	assertWithSubsecond(t, time.WithMicrosecond, 1, NewSmalltime(2003, 11, 15, 8, 30, 55, 1))
+	assertWithSubsecond(t, time.WithNanosecond, 999999999, NewSmalltime(2003, 11, 15, 8, 30, 55, 999999))
+
+	assertWithSubsecondError(t, time.WithMillisecond, 1000)
+	assertWithSubsecondError(t, time.WithMicrosecond, -1)
+	assertWithSubsecondError(t, time.WithNanosecond, 1000000000)
+}
+
+func assertWithSubsecond(t *testing.T, setter func(int) (Smalltime, error), value int, expected Smalltime) {
+	actual, err := setter(value)
+	if err != nil {
+		t.Errorf("Unexpected error setting %v: %v", value, err)
+	} else if actual != expected {
+		t.Errorf("Expected setting %v to give %016x but got %016x", value, expected, actual)
+	}
+}
+
+func assertWithSubsecondError(t *testing.T, setter func(int) (Smalltime, error), value int) {
+	if _, err := setter(value); err == nil {
+		t.Errorf("Expected error setting %v", value)
+	} else if _, ok := err.(*RangeError); !ok {
+		t.Errorf("Expected a *RangeError setting %v but got %T", value, err)
+	}
+}
+
+func assertWith(t *testing.T, actual Smalltime, err error, expected Smalltime) {
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	} else if actual != expected {
+		t.Errorf("Expected %016x but got %016x", expected, actual)
+	}
+}
+
+func assertWithError(t *testing.T, actual Smalltime, err error, original Smalltime) {
+	if err == nil {
+		t.Errorf("Expected an error but got %016x", actual)
+	} else if actual != original {
+		t.Errorf("Expected %016x to be unchanged on error but got %016x", original, actual)
+	}
+}
+
+func TestFieldSetters(t *testing.T) {
+	time := NewSmalltime(2003, 11, 15, 8, 30, 55, 1402)
+
+	v, err := time.WithYear(-44, KeepDay)
+	assertWith(t, v, err, NewSmalltime(-44, 11, 15, 8, 30, 55, 1402))
+	v, err = time.WithMonth(2, KeepDay)
+	assertWith(t, v, err, NewSmalltime(2003, 2, 15, 8, 30, 55, 1402))
+	v, err = time.WithDay(30)
+	assertWith(t, v, err, NewSmalltime(2003, 11, 30, 8, 30, 55, 1402))
+	v, err = time.WithHour(23)
+	assertWith(t, v, err, NewSmalltime(2003, 11, 15, 23, 30, 55, 1402))
+	v, err = time.WithMinute(0)
+	assertWith(t, v, err, NewSmalltime(2003, 11, 15, 8, 0, 55, 1402))
+	v, err = time.WithSecond(60)
+	assertWith(t, v, err, NewSmalltime(2003, 11, 15, 8, 30, 60, 1402))
+
+	v, err = time.WithYear(131072, KeepDay)
+	assertWithError(t, v, err, time)
+	v, err = time.WithMonth(13, KeepDay)
+	assertWithError(t, v, err, time)
+	v, err = time.WithDay(31)
+	assertWithError(t, v, err, time)
+	v, err = time.WithHour(24)
+	assertWithError(t, v, err, time)
+	v, err = time.WithMinute(-1)
+	assertWithError(t, v, err, time)
+	v, err = time.WithSecond(61)
+	assertWithError(t, v, err, time)
+}
+
+func TestDayPolicy(t *testing.T) {
+	jan31 := NewSmalltime(2000, 1, 31, 12, 0, 0, 0)
+
+	v, err := jan31.WithMonth(2, KeepDay)
+	assertWith(t, v, err, NewSmalltime(2000, 2, 31, 12, 0, 0, 0))
+	v, err = jan31.WithMonth(2, CheckDay)
+	assertWithError(t, v, err, jan31)
+	v, err = jan31.WithMonth(2, ClampDay)
+	assertWith(t, v, err, NewSmalltime(2000, 2, 29, 12, 0, 0, 0))
+	v, err = jan31.WithMonth(3, CheckDay)
+	assertWith(t, v, err, NewSmalltime(2000, 3, 31, 12, 0, 0, 0))
+
+	feb29 := NewSmalltime(2000, 2, 29, 12, 0, 0, 0)
+	v, err = feb29.WithYear(2001, CheckDay)
+	assertWithError(t, v, err, feb29)
+	v, err = feb29.WithYear(2001, ClampDay)
+	assertWith(t, v, err, NewSmalltime(2001, 2, 28, 12, 0, 0, 0))
+	v, err = feb29.WithYear(2004, CheckDay)
+	assertWith(t, v, err, NewSmalltime(2004, 2, 29, 12, 0, 0, 0))
+	v, err = feb29.WithYear(-4, CheckDay)
+	assertWith(t, v, err, NewSmalltime(-4, 2, 29, 12, 0, 0, 0))
+
+	// The month field can hold 13-15, which has no length to check against
+	month13 := NewSmalltime(2000, 13, 1, 0, 0, 0, 0)
+	v, err = month13.WithYear(2001, ClampDay)
+	assertWithError(t, v, err, month13)
+	v, err = month13.WithYear(2001, CheckDay)
+	assertWithError(t, v, err, month13)
+	v, err = month13.WithYear(2001, KeepDay)
+	assertWith(t, v, err, NewSmalltime(2001, 13, 1, 0, 0, 0, 0))
+}
+
+func TestNegativeYearLeapRules(t *testing.T) {
+	// Astronomical year numbering: year 0 is 1 BC, -4 is 5 BC, and so on.
+	leapYears := []int{0, -4, -8, -396, -400, -800, -2000, -131072}
+	commonYears := []int{-1, -2, -3, -100, -200, -300, -500, -1900, -131071}
+	for _, year := range leapYears {
+		if !isLeapYear(year) {
+			t.Errorf("Expected year %v to be a leap year", year)
+		}
+		if err := NewSmalltime(year, 2, 29, 0, 0, 0, 0).Validate(); err != nil {
+			t.Errorf("Expected %v-02-29 to be valid but got %v", year, err)
+		}
+		assertYmdToDoy(t, year, 12, 31, 366)
+		assertYdToMd(t, year, 60, 2, 29)
+	}
+	for _, year := range commonYears {
+		if isLeapYear(year) {
+			t.Errorf("Expected year %v to be a common year", year)
+		}
+		if err := NewSmalltime(year, 2, 29, 0, 0, 0, 0).Validate(); err == nil {
+			t.Errorf("Expected %v-02-29 to be invalid", year)
+		}
+		assertYmdToDoy(t, year, 12, 31, 365)
+		assertYdToMd(t, year, 60, 3, 1)
+	}
+}
+
+func TestNegativeYearsMatchTime(t *testing.T) {
+	assertTimeEquivalence(t, 0, 2, 29, 12, 0, 0, 0)
+	assertTimeEquivalence(t, -1, 12, 31, 23, 59, 59, 999999)
+	assertTimeEquivalence(t, -4, 2, 29, 0, 0, 0, 0)
+	assertTimeEquivalence(t, -100, 3, 1, 0, 0, 0, 0)
+	assertTimeEquivalence(t, -44, 3, 15, 12, 0, 0, 0)
+	assertTimeEquivalence(t, -131072, 1, 1, 0, 0, 0, 0)
+
+	for _, year := range []int{0, -1, -4, -100, -400, -44, -131072} {
+		for _, doy := range []int{1, 59, 60, 365} {
+			smtime := NewSmalltimeWithDoy(year, doy, 0, 0, 0, 0)
+			if actual := smtime.AsTime().YearDay(); actual != doy {
+				t.Errorf("Expected year %v doy %v to convert to time.Time doy %v but got %v", year, doy, doy, actual)
+			}
+		}
+	}
+}
+
+func TestOrderingAcrossYearZero(t *testing.T) {
+	ordered := []Smalltime{
+		MinSmalltime,
+		NewSmalltime(-131072, 12, 31, 23, 59, 60, 999999),
+		NewSmalltime(-2, 12, 31, 23, 59, 59, 999999),
+		NewSmalltime(-1, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(-1, 12, 31, 23, 59, 60, 999999),
+		NewSmalltime(0, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(0, 1, 1, 0, 0, 0, 1),
+		NewSmalltime(0, 12, 31, 23, 59, 59, 999999),
+		NewSmalltime(1, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(131071, 1, 1, 0, 0, 0, 0),
+		MaxSmalltime,
+	}
+	for i := 1; i < len(ordered); i++ {
+		assertGreater(t, ordered[i], ordered[i-1])
+	}
+}
+
+func TestMinMaxSmalltime(t *testing.T) {
+	assertDecode(t, MinSmalltime, -131072, 1, 1, 0, 0, 0, 0)
+	assertDecode(t, MaxSmalltime, 131071, 12, 31, 23, 59, 59, 999999)
+	assertEncode(t, -131072, 1, 1, 0, 0, 0, 0, MinSmalltime)
+	assertEncode(t, 131071, 12, 31, 23, 59, 59, 999999, MaxSmalltime)
+	if err := MinSmalltime.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := MaxSmalltime.Validate(); err != nil {
+		t.Error(err)
+	}
+	if _, err := MaxSmalltime.WithYear(131072, CheckDay); err == nil {
+		t.Errorf("Expected year 131072 to be out of range")
+	}
+}
+
+func TestSmalltimeFromTimeChecked(t *testing.T) {
+	gotime := time.Date(1999, 2, 15, 12, 8, 45, 9122000, time.UTC)
+	if value, err := SmalltimeFromTimeChecked(gotime); err != nil || value != NewSmalltime(1999, 2, 15, 12, 8, 45, 9122) {
+		t.Errorf("Unexpected result %v (error %v)", Format(value), err)
+	}
+	gotime = time.Date(131071, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	if value, err := SmalltimeFromTimeChecked(gotime); err != nil || value != NewSmalltime(131071, 12, 31, 23, 59, 59, 999999) {
+		t.Errorf("Unexpected result %v (error %v)", Format(value), err)
+	}
+
+	for _, year := range []int{-131073, 131072, 300000} {
+		_, err := SmalltimeFromTimeChecked(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC))
+		if !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected year %v to give ErrOutOfRange but got %v", year, err)
+		}
+		var rangeErr *RangeError
+		if !errors.As(err, &rangeErr) || rangeErr.Field != "year" || rangeErr.Value != year {
+			t.Errorf("Expected a RangeError for year %v but got %#v", year, err)
+		}
+	}
+}
+
+func TestSmalltimeFromTimeSaturating(t *testing.T) {
+	gotime := time.Date(1999, 2, 15, 12, 8, 45, 9122000, time.UTC)
+	if value := SmalltimeFromTimeSaturating(gotime); value != NewSmalltime(1999, 2, 15, 12, 8, 45, 9122) {
+		t.Errorf("Unexpected result %v", Format(value))
+	}
+	if value := SmalltimeFromTimeSaturating(time.Date(-131073, 12, 31, 0, 0, 0, 0, time.UTC)); value != MinSmalltime {
+		t.Errorf("Expected MinSmalltime but got %v", Format(value))
+	}
+	if value := SmalltimeFromTimeSaturating(time.Date(131072, 1, 1, 0, 0, 0, 0, time.UTC)); value != MaxSmalltime {
+		t.Errorf("Expected MaxSmalltime but got %v", Format(value))
+	}
+	for _, bound := range []Smalltime{MinSmalltime, MaxSmalltime} {
+		if value, err := SmalltimeFromTimeChecked(bound.AsTime()); err != nil || value != bound {
+			t.Errorf("Expected %v to round trip but got %v (error %v)", Format(bound), Format(value), err)
+		}
+	}
+}
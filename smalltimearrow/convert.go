@@ -0,0 +1,215 @@
+/*
+Package smalltimearrow converts between Apache Arrow timestamp arrays and
+slices of Smalltime or Nanotime, and provides Arrow extension types that store
+the raw 64-bit encodings, so that extracting date & time fields in queries is
+just a shift and mask.
+
+Arrow timestamps with a time zone hold UTC instants, and those without (naive
+timestamps) hold wall clock values; in both cases the stored values map
+directly onto smalltime's UTC fields, so the time zone metadata is carried
+through unchanged.
+
+Null array elements are converted to the zero value.
+*/
+package smalltimearrow
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/kstenerud/go-smalltime"
+)
+
+// Multiplier to convert a value in the given unit to nanoseconds.
+func unitNanos(unit arrow.TimeUnit) int64 {
+	switch unit {
+	case arrow.Second:
+		return 1000000000
+	case arrow.Millisecond:
+		return 1000000
+	case arrow.Microsecond:
+		return 1000
+	default:
+		return 1
+	}
+}
+
+func floorDiv(value, divisor int64) int64 {
+	if value < 0 && value%divisor != 0 {
+		return value/divisor - 1
+	}
+	return value / divisor
+}
+
+// Converts values in place from one unit (expressed in nanoseconds) to another,
+// returning an error if a value doesn't fit into 64 bits in the new unit.
+// Elements that valid marks as null are left alone (nil means all are valid).
+func rescale(values []int64, valid []bool, fromNanos, toNanos int64) error {
+	switch {
+	case fromNanos > toNanos:
+		factor := fromNanos / toNanos
+		for i, v := range values {
+			if valid != nil && !valid[i] {
+				continue
+			}
+			if v > math.MaxInt64/factor || v < math.MinInt64/factor {
+				return fmt.Errorf("smalltimearrow: value %v at index %v overflows when converted to a finer unit", v, i)
+			}
+			values[i] = v * factor
+		}
+	case fromNanos < toNanos:
+		factor := toNanos / fromNanos
+		for i, v := range values {
+			values[i] = floorDiv(v, factor)
+		}
+	}
+	return nil
+}
+
+func timestampType(arr *array.Timestamp) *arrow.TimestampType {
+	return arr.DataType().(*arrow.TimestampType)
+}
+
+func unixValues(arr *array.Timestamp, unit arrow.TimeUnit) ([]int64, error) {
+	values := make([]int64, arr.Len())
+	for i := range values {
+		if arr.IsValid(i) {
+			values[i] = int64(arr.Value(i))
+		}
+	}
+	err := rescale(values, nil, unitNanos(timestampType(arr).Unit), unitNanos(unit))
+	return values, err
+}
+
+func newTimestampArray(mem memory.Allocator, values []int64, valid []bool, unit arrow.TimeUnit, timezone string) *array.Timestamp {
+	builder := array.NewTimestampBuilder(mem, &arrow.TimestampType{Unit: unit, TimeZone: timezone})
+	defer builder.Release()
+	timestamps := make([]arrow.Timestamp, len(values))
+	for i, v := range values {
+		timestamps[i] = arrow.Timestamp(v)
+	}
+	builder.AppendValues(timestamps, valid)
+	return builder.NewTimestampArray()
+}
+
+// Smalltime's range in Unix microseconds.
+var minSmalltimeUnixMicros, maxSmalltimeUnixMicros = func() (int64, int64) {
+	var micros [2]int64
+	smalltime.DecodeSmalltimes(micros[:], []smalltime.Smalltime{smalltime.MinSmalltime, smalltime.MaxSmalltime})
+	return micros[0], micros[1]
+}()
+
+// SmalltimesFromArray converts an Arrow timestamp array to Smalltimes,
+// returning an error if any value falls outside of Smalltime's range.
+// Nanosecond timestamps are truncated to the microsecond.
+func SmalltimesFromArray(arr *array.Timestamp) ([]smalltime.Smalltime, error) {
+	micros, err := unixValues(arr, arrow.Microsecond)
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range micros {
+		if arr.IsValid(i) && (value < minSmalltimeUnixMicros || value > maxSmalltimeUnixMicros) {
+			return nil, fmt.Errorf("smalltimearrow: value %v at index %v is outside of Smalltime's range", arr.Value(i), i)
+		}
+	}
+	result := make([]smalltime.Smalltime, len(micros))
+	smalltime.EncodeSmalltimes(result, micros)
+	for i := range result {
+		if arr.IsNull(i) {
+			result[i] = 0
+		}
+	}
+	return result, nil
+}
+
+// NanotimesFromArray converts an Arrow timestamp array to Nanotimes, returning
+// an error if any value falls outside of Nanotime's range (1970 - 2225).
+func NanotimesFromArray(arr *array.Timestamp) ([]smalltime.Nanotime, error) {
+	result := make([]smalltime.Nanotime, arr.Len())
+	perSecond := 1000000000 / unitNanos(timestampType(arr).Unit)
+	for i := range result {
+		if arr.IsValid(i) {
+			if value := int64(arr.Value(i)); value < 0 || value/perSecond > maxNanotimeUnixSeconds {
+				return nil, fmt.Errorf("smalltimearrow: value %v at index %v is outside of Nanotime's range", value, i)
+			}
+		}
+	}
+	// The range check above keeps the values within int64 nanoseconds.
+	nanos, err := unixValues(arr, arrow.Nanosecond)
+	if err != nil {
+		return nil, err
+	}
+	smalltime.EncodeNanotimes(result, nanos)
+	for i := range result {
+		if arr.IsNull(i) {
+			result[i] = 0
+		}
+	}
+	return result, nil
+}
+
+// 2225-12-31T23:59:59Z
+const maxNanotimeUnixSeconds = 8078572799
+
+// Returns an error for the first non-null value that is zero, a sentinel, or
+// has an out of range field, since it has no meaningful Unix time.
+func checkValues[T interface {
+	IsZero() bool
+	IsInfinite() bool
+	Validate() error
+}](values []T, valid []bool) error {
+	for i, value := range values {
+		if valid != nil && !valid[i] {
+			continue
+		}
+		var err error
+		switch {
+		case value.IsZero():
+			err = errors.New("zero value")
+		case value.IsInfinite():
+			err = errors.New("infinite value")
+		default:
+			err = value.Validate()
+		}
+		if err != nil {
+			return fmt.Errorf("smalltimearrow: invalid value at index %v: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NewArrayFromSmalltimes creates an Arrow timestamp array from Smalltimes.
+// valid marks which elements are non-null (nil means all are valid), as with
+// Arrow's AppendValues. Non-null elements must be valid, non-zero, and not
+// infinite. Converting to a coarser unit truncates, and converting to
+// nanoseconds returns an error for a value outside of the years 1678 - 2261.
+func NewArrayFromSmalltimes(mem memory.Allocator, values []smalltime.Smalltime, valid []bool, unit arrow.TimeUnit, timezone string) (*array.Timestamp, error) {
+	if err := checkValues(values, valid); err != nil {
+		return nil, err
+	}
+	unixValues := make([]int64, len(values))
+	smalltime.DecodeSmalltimes(unixValues, values)
+	if err := rescale(unixValues, valid, unitNanos(arrow.Microsecond), unitNanos(unit)); err != nil {
+		return nil, err
+	}
+	return newTimestampArray(mem, unixValues, valid, unit, timezone), nil
+}
+
+// NewArrayFromNanotimes creates an Arrow timestamp array from Nanotimes.
+// valid marks which elements are non-null (nil means all are valid), as with
+// Arrow's AppendValues. Non-null elements must be valid, non-zero, and not
+// infinite. Converting to a coarser unit truncates.
+func NewArrayFromNanotimes(mem memory.Allocator, values []smalltime.Nanotime, valid []bool, unit arrow.TimeUnit, timezone string) (*array.Timestamp, error) {
+	if err := checkValues(values, valid); err != nil {
+		return nil, err
+	}
+	unixValues := make([]int64, len(values))
+	smalltime.DecodeNanotimes(unixValues, values)
+	// Nanoseconds are the finest unit, so this only ever divides.
+	_ = rescale(unixValues, valid, unitNanos(arrow.Nanosecond), unitNanos(unit))
+	return newTimestampArray(mem, unixValues, valid, unit, timezone), nil
+}
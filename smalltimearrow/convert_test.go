@@ -0,0 +1,357 @@
+package smalltimearrow
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/kstenerud/go-smalltime"
+)
+
+var update = flag.Bool("update", false, "regenerate the fixture files in testdata")
+
+const ipcFixture = "testdata/timestamps_micros.arrow"
+const parquetFixture = "testdata/timestamps_micros.parquet"
+
+var fixtureValues = []smalltime.Smalltime{
+	smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 9122),
+	smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 999999),
+	0,
+	smalltime.NewSmalltime(2000, 2, 29, 0, 0, 0, 0),
+	smalltime.NewSmalltime(9999, 12, 31, 23, 59, 59, 999999),
+}
+var fixtureValid = []bool{true, true, false, true, true}
+
+func fixtureSchema() *arrow.Schema {
+	return arrow.NewSchema([]arrow.Field{
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+	}, nil)
+}
+
+func fixtureRecord(t *testing.T, mem memory.Allocator) arrow.Record {
+	column, err := NewArrayFromSmalltimes(mem, fixtureValues, fixtureValid, arrow.Microsecond, "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer column.Release()
+	return array.NewRecord(fixtureSchema(), []arrow.Array{column}, int64(column.Len()))
+}
+
+func writeFixtures(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	record := fixtureRecord(t, mem)
+	defer record.Release()
+
+	out, err := os.Create(ipcFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer, err := ipc.NewFileWriter(out, ipc.WithSchema(record.Schema()), ipc.WithAllocator(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+
+	out, err = os.Create(parquetFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := array.NewTableFromRecords(record.Schema(), []arrow.Record{record})
+	defer table.Release()
+	if err := pqarrow.WriteTable(table, out, 1024, nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFixtureColumn(t *testing.T, column arrow.Array) {
+	timestamps, ok := column.(*array.Timestamp)
+	if !ok {
+		t.Fatalf("Expected a timestamp column but got %v", column.DataType())
+	}
+	if unit := timestamps.DataType().(*arrow.TimestampType).Unit; unit != arrow.Microsecond {
+		t.Errorf("Expected microsecond unit but got %v", unit)
+	}
+	actual, err := SmalltimesFromArray(timestamps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range fixtureValues {
+		if actual[i] != expected {
+			t.Errorf("Index %v: Expected %v but got %v", i, smalltime.Format(expected), smalltime.Format(actual[i]))
+		}
+		if timestamps.IsValid(i) != fixtureValid[i] {
+			t.Errorf("Index %v: Expected validity %v", i, fixtureValid[i])
+		}
+	}
+}
+
+func TestIPCFixture(t *testing.T) {
+	if *update {
+		writeFixtures(t)
+	}
+	in, err := os.Open(ipcFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	reader, err := ipc.NewFileReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	record, err := reader.Record(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFixtureColumn(t, record.Column(0))
+}
+
+func TestParquetFixture(t *testing.T) {
+	reader, err := file.OpenParquetFile(parquetFixture, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	fileReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.NewGoAllocator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := fileReader.ReadTable(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Release()
+	assertFixtureColumn(t, table.Column(0).Data().Chunk(0))
+}
+
+func TestUnits(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	values := []smalltime.Smalltime{
+		smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 9122),
+		smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 500000),
+	}
+	for _, vector := range []struct {
+		unit     arrow.TimeUnit
+		raw      []arrow.Timestamp
+		expected []smalltime.Smalltime
+	}{
+		{arrow.Second, []arrow.Timestamp{919080525, -1}, []smalltime.Smalltime{
+			smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 0),
+			smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 0),
+		}},
+		{arrow.Millisecond, []arrow.Timestamp{919080525009, -500}, []smalltime.Smalltime{
+			smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 9000),
+			smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 500000),
+		}},
+		{arrow.Microsecond, []arrow.Timestamp{919080525009122, -500000}, values},
+		{arrow.Nanosecond, []arrow.Timestamp{919080525009122000, -500000000}, values},
+	} {
+		arr, err := NewArrayFromSmalltimes(mem, values, nil, vector.unit, "Europe/Berlin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tz := arr.DataType().(*arrow.TimestampType).TimeZone; tz != "Europe/Berlin" {
+			t.Errorf("Expected time zone to be preserved but got %v", tz)
+		}
+		for i, raw := range vector.raw {
+			if arr.Value(i) != raw {
+				t.Errorf("%v: Expected raw value %v but got %v", vector.unit, raw, arr.Value(i))
+			}
+		}
+		actual, err := SmalltimesFromArray(arr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, expected := range vector.expected {
+			if actual[i] != expected {
+				t.Errorf("%v: Expected %v but got %v", vector.unit, smalltime.Format(expected), smalltime.Format(actual[i]))
+			}
+		}
+		arr.Release()
+	}
+}
+
+func TestUnitOverflow(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	distant := []smalltime.Smalltime{smalltime.NewSmalltime(1999, 2, 15, 0, 0, 0, 0), smalltime.NewSmalltime(3000, 1, 1, 0, 0, 0, 0)}
+	if _, err := NewArrayFromSmalltimes(mem, distant, nil, arrow.Nanosecond, ""); err == nil {
+		t.Errorf("Expected the year 3000 to overflow nanoseconds")
+	}
+	// Null elements aren't converted
+	arr, err := NewArrayFromSmalltimes(mem, distant, []bool{true, false}, arrow.Nanosecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr.Release()
+
+	builder := array.NewTimestampBuilder(mem, &arrow.TimestampType{Unit: arrow.Second})
+	defer builder.Release()
+	builder.AppendValues([]arrow.Timestamp{0, math.MaxInt64 / 1000}, nil)
+	seconds := builder.NewTimestampArray()
+	defer seconds.Release()
+	if _, err := SmalltimesFromArray(seconds); err == nil {
+		t.Errorf("Expected %v seconds to overflow microseconds", seconds.Value(1))
+	}
+}
+
+func TestSmalltimeRange(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	builder := array.NewTimestampBuilder(mem, &arrow.TimestampType{Unit: arrow.Second})
+	defer builder.Release()
+	// 131072-01-01T00:00:00Z, then -131073-12-31T23:59:59Z
+	builder.AppendValues([]arrow.Timestamp{4074065596800, -4198400035201}, nil)
+	arr := builder.NewTimestampArray()
+	defer arr.Release()
+	for i := 0; i < arr.Len(); i++ {
+		slice := array.NewSlice(arr, int64(i), int64(i+1)).(*array.Timestamp)
+		if actual, err := SmalltimesFromArray(slice); err == nil {
+			t.Errorf("Expected %v to be out of range but got %v", slice.Value(0), smalltime.Format(actual[0]))
+		}
+		slice.Release()
+	}
+
+	bounds := []smalltime.Smalltime{smalltime.MinSmalltime, smalltime.MaxSmalltime}
+	boundsArray, err := NewArrayFromSmalltimes(mem, bounds, nil, arrow.Microsecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer boundsArray.Release()
+	if actual, err := SmalltimesFromArray(boundsArray); err != nil || actual[0] != bounds[0] || actual[1] != bounds[1] {
+		t.Errorf("Expected the bounds to round trip but got %v (%v)", actual, err)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	for _, value := range []smalltime.Smalltime{
+		0,
+		smalltime.PositiveInfinitySmalltime,
+		smalltime.NegativeInfinitySmalltime,
+		smalltime.NewSmalltime(2001, 2, 29, 0, 0, 0, 0),
+	} {
+		if _, err := NewArrayFromSmalltimes(mem, []smalltime.Smalltime{value}, nil, arrow.Microsecond, ""); err == nil {
+			t.Errorf("Expected %016x to be rejected", value)
+		}
+	}
+	arr, err := NewArrayFromSmalltimes(mem, []smalltime.Smalltime{0}, []bool{false}, arrow.Microsecond, "")
+	if err != nil {
+		t.Errorf("Expected a null zero value to be accepted but got %v", err)
+	} else {
+		arr.Release()
+	}
+
+	for _, value := range []smalltime.Nanotime{0, smalltime.PositiveInfinityNanotime, smalltime.NewNanotime(2000, 1, 1, 24, 0, 0, 0)} {
+		if _, err := NewArrayFromNanotimes(mem, []smalltime.Nanotime{value}, nil, arrow.Nanosecond, ""); err == nil {
+			t.Errorf("Expected %016x to be rejected", value)
+		}
+	}
+}
+
+func TestNanotimes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	values := []smalltime.Nanotime{
+		smalltime.NewNanotime(1999, 2, 15, 12, 8, 45, 10159122),
+		smalltime.NewNanotime(2225, 12, 31, 23, 59, 59, 999999999),
+	}
+	arr, err := NewArrayFromNanotimes(mem, values, nil, arrow.Nanosecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer arr.Release()
+	if arr.Value(0) != 919080525010159122 {
+		t.Errorf("Unexpected raw value %v", arr.Value(0))
+	}
+	actual, err := NanotimesFromArray(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range values {
+		if actual[i] != expected {
+			t.Errorf("Expected %v but got %v", smalltime.Format(expected), smalltime.Format(actual[i]))
+		}
+	}
+
+	before, err := NewArrayFromSmalltimes(mem, []smalltime.Smalltime{smalltime.NewSmalltime(1969, 1, 1, 0, 0, 0, 0)}, nil, arrow.Second, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer before.Release()
+	if _, err := NanotimesFromArray(before); err == nil {
+		t.Errorf("Expected 1969 to be outside of Nanotime's range")
+	}
+}
+
+func TestExtensionTypes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	values := []smalltime.Smalltime{
+		smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 9122),
+		0,
+		smalltime.NewSmalltime(-44, 3, 15, 12, 0, 0, 0),
+	}
+	arr := NewSmalltimeArray(mem, values, []bool{true, false, true})
+	defer arr.Release()
+	if arr.Value(2) != values[2] || arr.Values()[0] != values[0] || !arr.IsNull(1) {
+		t.Errorf("Unexpected array contents %v", arr)
+	}
+	if str := arr.String(); str != "[1999-02-15T12:08:45.009122Z (null) -0044-03-15T12:00:00Z]" {
+		t.Errorf("Unexpected string %v", str)
+	}
+
+	// Round trip through IPC to exercise the registered type
+	schema := arrow.NewSchema([]arrow.Field{{Name: "st", Type: arr.DataType(), Nullable: true}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer record.Release()
+	var buffer bytes.Buffer
+	writer := ipc.NewWriter(&buffer, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err := writer.Write(record); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+	reader, err := ipc.NewReader(&buffer, ipc.WithAllocator(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+	decoded, ok := reader.Record().Column(0).(*SmalltimeArray)
+	if !ok {
+		t.Fatalf("Expected a SmalltimeArray but got %T", reader.Record().Column(0))
+	}
+	if decoded.Value(0) != values[0] || decoded.Value(2) != values[2] || !decoded.IsNull(1) {
+		t.Errorf("Unexpected decoded contents %v", decoded)
+	}
+
+	nanotimes := NewNanotimeArray(mem, []smalltime.Nanotime{smalltime.NewNanotime(2000, 1, 1, 0, 0, 0, 1)}, nil)
+	defer nanotimes.Release()
+	if nanotimes.Values()[0].Nanosecond() != 1 || nanotimes.DataType().(arrow.ExtensionType).ExtensionName() != "smalltime.nanotime" {
+		t.Errorf("Unexpected array contents %v", nanotimes)
+	}
+}
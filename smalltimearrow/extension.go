@@ -0,0 +1,177 @@
+package smalltimearrow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/kstenerud/go-smalltime"
+)
+
+// SmalltimeType is an Arrow extension type storing raw Smalltime values in an
+// int64 array. It is registered under the name "smalltime.smalltime".
+type SmalltimeType struct {
+	arrow.ExtensionBase
+}
+
+func NewSmalltimeType() *SmalltimeType {
+	return &SmalltimeType{ExtensionBase: arrow.ExtensionBase{Storage: arrow.PrimitiveTypes.Int64}}
+}
+
+func (t *SmalltimeType) ArrayType() reflect.Type { return reflect.TypeOf(SmalltimeArray{}) }
+
+func (t *SmalltimeType) ExtensionName() string { return "smalltime.smalltime" }
+
+func (t *SmalltimeType) Serialize() string { return "" }
+
+func (t *SmalltimeType) String() string { return fmt.Sprintf("extension<%s>", t.ExtensionName()) }
+
+func (t *SmalltimeType) ExtensionEquals(other arrow.ExtensionType) bool {
+	return t.ExtensionName() == other.ExtensionName()
+}
+
+func (t *SmalltimeType) Deserialize(storageType arrow.DataType, data string) (arrow.ExtensionType, error) {
+	if !arrow.TypeEqual(storageType, arrow.PrimitiveTypes.Int64) {
+		return nil, fmt.Errorf("smalltimearrow: invalid storage type for %v: %v", t.ExtensionName(), storageType)
+	}
+	return NewSmalltimeType(), nil
+}
+
+// SmalltimeArray is the array type of SmalltimeType.
+type SmalltimeArray struct {
+	array.ExtensionArrayBase
+}
+
+func (a *SmalltimeArray) Value(i int) smalltime.Smalltime {
+	return smalltime.Smalltime(a.Storage().(*array.Int64).Value(i))
+}
+
+// Values returns the underlying values without copying.
+func (a *SmalltimeArray) Values() []smalltime.Smalltime {
+	values := a.Storage().(*array.Int64).Int64Values()
+	return unsafe.Slice((*smalltime.Smalltime)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
+}
+
+func (a *SmalltimeArray) ValueStr(i int) string {
+	if a.IsNull(i) {
+		return array.NullValueStr
+	}
+	return smalltime.Format(a.Value(i))
+}
+
+func (a *SmalltimeArray) String() string {
+	return arrayString(a.Len(), a.ValueStr)
+}
+
+func (a *SmalltimeArray) GetOneForMarshal(i int) interface{} {
+	if a.IsNull(i) {
+		return nil
+	}
+	return a.ValueStr(i)
+}
+
+// NewSmalltimeArray creates a SmalltimeArray. valid marks which elements are
+// non-null (nil means all are valid), as with Arrow's AppendValues.
+func NewSmalltimeArray(mem memory.Allocator, values []smalltime.Smalltime, valid []bool) *SmalltimeArray {
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.AppendValues(unsafe.Slice((*int64)(unsafe.Pointer(unsafe.SliceData(values))), len(values)), valid)
+	storage := builder.NewInt64Array()
+	defer storage.Release()
+	return array.NewExtensionArrayWithStorage(NewSmalltimeType(), storage).(*SmalltimeArray)
+}
+
+// NanotimeType is an Arrow extension type storing raw Nanotime values in a
+// uint64 array. It is registered under the name "smalltime.nanotime".
+type NanotimeType struct {
+	arrow.ExtensionBase
+}
+
+func NewNanotimeType() *NanotimeType {
+	return &NanotimeType{ExtensionBase: arrow.ExtensionBase{Storage: arrow.PrimitiveTypes.Uint64}}
+}
+
+func (t *NanotimeType) ArrayType() reflect.Type { return reflect.TypeOf(NanotimeArray{}) }
+
+func (t *NanotimeType) ExtensionName() string { return "smalltime.nanotime" }
+
+func (t *NanotimeType) Serialize() string { return "" }
+
+func (t *NanotimeType) String() string { return fmt.Sprintf("extension<%s>", t.ExtensionName()) }
+
+func (t *NanotimeType) ExtensionEquals(other arrow.ExtensionType) bool {
+	return t.ExtensionName() == other.ExtensionName()
+}
+
+func (t *NanotimeType) Deserialize(storageType arrow.DataType, data string) (arrow.ExtensionType, error) {
+	if !arrow.TypeEqual(storageType, arrow.PrimitiveTypes.Uint64) {
+		return nil, fmt.Errorf("smalltimearrow: invalid storage type for %v: %v", t.ExtensionName(), storageType)
+	}
+	return NewNanotimeType(), nil
+}
+
+// NanotimeArray is the array type of NanotimeType.
+type NanotimeArray struct {
+	array.ExtensionArrayBase
+}
+
+func (a *NanotimeArray) Value(i int) smalltime.Nanotime {
+	return smalltime.Nanotime(a.Storage().(*array.Uint64).Value(i))
+}
+
+// Values returns the underlying values without copying.
+func (a *NanotimeArray) Values() []smalltime.Nanotime {
+	values := a.Storage().(*array.Uint64).Uint64Values()
+	return unsafe.Slice((*smalltime.Nanotime)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
+}
+
+func (a *NanotimeArray) ValueStr(i int) string {
+	if a.IsNull(i) {
+		return array.NullValueStr
+	}
+	return smalltime.Format(a.Value(i))
+}
+
+func (a *NanotimeArray) String() string {
+	return arrayString(a.Len(), a.ValueStr)
+}
+
+func (a *NanotimeArray) GetOneForMarshal(i int) interface{} {
+	if a.IsNull(i) {
+		return nil
+	}
+	return a.ValueStr(i)
+}
+
+// NewNanotimeArray creates a NanotimeArray. valid marks which elements are
+// non-null (nil means all are valid), as with Arrow's AppendValues.
+func NewNanotimeArray(mem memory.Allocator, values []smalltime.Nanotime, valid []bool) *NanotimeArray {
+	builder := array.NewUint64Builder(mem)
+	defer builder.Release()
+	builder.AppendValues(unsafe.Slice((*uint64)(unsafe.Pointer(unsafe.SliceData(values))), len(values)), valid)
+	storage := builder.NewUint64Array()
+	defer storage.Release()
+	return array.NewExtensionArrayWithStorage(NewNanotimeType(), storage).(*NanotimeArray)
+}
+
+func arrayString(length int, valueStr func(int) string) string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for i := 0; i < length; i++ {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(valueStr(i))
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+func init() {
+	arrow.RegisterExtensionType(NewSmalltimeType())
+	arrow.RegisterExtensionType(NewNanotimeType())
+}
@@ -0,0 +1,399 @@
+/*
+Package smalltimecbor encodes and decodes smalltime & nanotime values as CBOR
+(RFC 8949) tagged data items:
+
+  - Tag 0: RFC 3339 date/time string (preserves leap seconds).
+  - Tag 1: Epoch-based date/time, as an integer when there's no sub-second
+    portion, and as a float64 otherwise.
+  - TagSmalltime / TagNanotime: The raw 64-bit encoding as an integer.
+
+TagSmalltime and TagNanotime are not registered with IANA. Since POSIX time has
+no leap seconds, tag 1 converts second 60 to the last microsecond of second 59.
+
+The Smalltime and Nanotime types implement the MarshalCBOR and UnmarshalCBOR
+methods that CBOR libraries such as github.com/fxamacker/cbor look for, so
+that struct fields of these types are encoded with TagSmalltime or
+TagNanotime, and decoded from any of the supported tags.
+*/
+package smalltimecbor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kstenerud/go-smalltime"
+)
+
+const (
+	TagDateTimeString = 0
+	TagEpochDateTime  = 1
+	TagSmalltime      = 0x736d7400
+	TagNanotime       = 0x6e6d7400
+)
+
+const (
+	majorUnsigned = 0 << 5
+	majorNegative = 1 << 5
+	majorText     = 3 << 5
+	majorTag      = 6 << 5
+	majorSimple   = 7 << 5
+)
+
+const (
+	additionalFloat16 = 25
+	additionalFloat32 = 26
+	additionalFloat64 = 27
+)
+
+var ErrUnexpectedEnd = errors.New("smalltimecbor: unexpected end of data")
+var ErrUnsupportedItem = errors.New("smalltimecbor: unsupported data item")
+var ErrTrailingData = errors.New("smalltimecbor: trailing data after date/time item")
+
+func appendHead(dst []byte, major byte, value uint64) []byte {
+	switch {
+	case value < 24:
+		return append(dst, major|byte(value))
+	case value <= math.MaxUint8:
+		return append(dst, major|24, byte(value))
+	case value <= math.MaxUint16:
+		return appendBigEndian(append(dst, major|25), value, 2)
+	case value <= math.MaxUint32:
+		return appendBigEndian(append(dst, major|26), value, 4)
+	default:
+		return appendBigEndian(append(dst, major|27), value, 8)
+	}
+}
+
+func appendBigEndian(dst []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		dst = append(dst, byte(value>>(8*i)))
+	}
+	return dst
+}
+
+func appendInt(dst []byte, value int64) []byte {
+	if value < 0 {
+		return appendHead(dst, majorNegative, uint64(-1-value))
+	}
+	return appendHead(dst, majorUnsigned, uint64(value))
+}
+
+// Returns the major type, the additional info, the argument, and the number
+// of bytes consumed.
+func decodeHead(src []byte) (major byte, additional byte, value uint64, n int, err error) {
+	if len(src) < 1 {
+		return 0, 0, 0, 0, ErrUnexpectedEnd
+	}
+	major = src[0] & 0xe0
+	additional = src[0] & 0x1f
+	switch {
+	case additional < 24:
+		return major, additional, uint64(additional), 1, nil
+	case additional > 27:
+		return 0, 0, 0, 0, ErrUnsupportedItem
+	}
+	size := 1 << (additional - 24)
+	if len(src) < 1+size {
+		return 0, 0, 0, 0, ErrUnexpectedEnd
+	}
+	for _, b := range src[1 : 1+size] {
+		value = value<<8 | uint64(b)
+	}
+	return major, additional, value, 1 + size, nil
+}
+
+// A float64 can't hold nanosecond precision at present-day epoch values, so
+// leap seconds are clamped to the last microsecond of second 59 (anything
+// finer would round up into the following minute).
+func asTimeWithoutLeapSecond(t smalltime.Timestamp) time.Time {
+	if t.Second() == 60 {
+		return time.Date(t.Year(), time.Month(t.Month()), t.Day(), t.Hour(),
+			t.Minute(), 59, 999999000, time.UTC)
+	}
+	return t.AsTime()
+}
+
+func appendEpoch(dst []byte, t smalltime.Timestamp) []byte {
+	dst = appendHead(dst, majorTag, TagEpochDateTime)
+	gotime := asTimeWithoutLeapSecond(t)
+	if gotime.Nanosecond() == 0 {
+		return appendInt(dst, gotime.Unix())
+	}
+	seconds := float64(gotime.Unix()) + float64(gotime.Nanosecond())/1e9
+	return appendBigEndian(append(dst, majorSimple|additionalFloat64), math.Float64bits(seconds), 8)
+}
+
+func appendString(dst []byte, t smalltime.Timestamp) ([]byte, error) {
+	if t.Year() < 0 || t.Year() > 9999 {
+		return dst, fmt.Errorf("smalltimecbor: year %v cannot be represented in RFC 3339", t.Year())
+	}
+	str := smalltime.Format(t)
+	dst = appendHead(dst, majorTag, TagDateTimeString)
+	dst = appendHead(dst, majorText, uint64(len(str)))
+	return append(dst, str...), nil
+}
+
+func float16ToFloat64(bits uint16) float64 {
+	exponent := int(bits>>10) & 0x1f
+	mantissa := float64(bits & 0x3ff)
+	var value float64
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		if mantissa == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if bits&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
+
+func decodeEpoch(src []byte) (time.Time, int, error) {
+	major, additional, value, n, err := decodeHead(src)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	switch major {
+	case majorUnsigned:
+		if value > math.MaxInt64 {
+			return time.Time{}, 0, ErrUnsupportedItem
+		}
+		return time.Unix(int64(value), 0).UTC(), n, nil
+	case majorNegative:
+		if value > math.MaxInt64 {
+			return time.Time{}, 0, ErrUnsupportedItem
+		}
+		return time.Unix(-1-int64(value), 0).UTC(), n, nil
+	case majorSimple:
+		var seconds float64
+		switch additional {
+		case additionalFloat16:
+			seconds = float16ToFloat64(uint16(value))
+		case additionalFloat32:
+			seconds = float64(math.Float32frombits(uint32(value)))
+		case additionalFloat64:
+			seconds = math.Float64frombits(value)
+		default:
+			return time.Time{}, 0, ErrUnsupportedItem
+		}
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return time.Time{}, 0, ErrUnsupportedItem
+		}
+		whole, fraction := math.Modf(seconds)
+		nanos := math.Round(fraction * 1e9)
+		return time.Unix(int64(whole), int64(nanos)).UTC(), n, nil
+	}
+	return time.Time{}, 0, ErrUnsupportedItem
+}
+
+// Parses an RFC 3339 date/time, returning the UTC time and whether it was a
+// leap second (which time.Parse doesn't support).
+func parseRFC3339(str string) (time.Time, bool, error) {
+	leapSecond := len(str) > 19 && str[16] == ':' && str[17:19] == "60"
+	if leapSecond {
+		str = str[:17] + "59" + str[19:]
+	}
+	gotime, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("smalltimecbor: %w", err)
+	}
+	return gotime.UTC(), leapSecond, nil
+}
+
+func decodeString(src []byte) (time.Time, bool, int, error) {
+	major, _, length, n, err := decodeHead(src)
+	if err != nil {
+		return time.Time{}, false, 0, err
+	}
+	if major != majorText {
+		return time.Time{}, false, 0, ErrUnsupportedItem
+	}
+	if uint64(len(src)-n) < length {
+		return time.Time{}, false, 0, ErrUnexpectedEnd
+	}
+	end := n + int(length)
+	gotime, leapSecond, err := parseRFC3339(string(src[n:end]))
+	return gotime, leapSecond, end, err
+}
+
+// AppendSmalltimeEpoch appends t as a tag 1 epoch-based date/time.
+func AppendSmalltimeEpoch(dst []byte, t smalltime.Smalltime) []byte {
+	return appendEpoch(dst, t)
+}
+
+// AppendSmalltimeString appends t as a tag 0 RFC 3339 string. Only years 0000
+// to 9999 can be represented.
+func AppendSmalltimeString(dst []byte, t smalltime.Smalltime) ([]byte, error) {
+	return appendString(dst, t)
+}
+
+// AppendSmalltimeRaw appends t's raw encoding tagged with TagSmalltime.
+func AppendSmalltimeRaw(dst []byte, t smalltime.Smalltime) []byte {
+	return appendInt(appendHead(dst, majorTag, TagSmalltime), int64(t))
+}
+
+// DecodeSmalltime decodes a tag 0, tag 1, or TagSmalltime data item, returning
+// the value and the number of bytes consumed.
+func DecodeSmalltime(src []byte) (smalltime.Smalltime, int, error) {
+	major, _, tag, n, err := decodeHead(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	if major != majorTag {
+		return 0, 0, ErrUnsupportedItem
+	}
+	switch tag {
+	case TagDateTimeString:
+		gotime, leapSecond, length, err := decodeString(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		t, err := smalltime.SmalltimeFromTimeChecked(gotime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("smalltimecbor: %w", err)
+		}
+		if leapSecond {
+			t, _ = t.WithSecond(60)
+		}
+		return t, n + length, nil
+	case TagEpochDateTime:
+		gotime, length, err := decodeEpoch(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		t, err := smalltime.SmalltimeFromTimeChecked(gotime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("smalltimecbor: %w", err)
+		}
+		return t, n + length, nil
+	case TagSmalltime:
+		major, _, value, length, err := decodeHead(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		switch major {
+		case majorUnsigned:
+			return smalltime.Smalltime(value), n + length, nil
+		case majorNegative:
+			return smalltime.Smalltime(-1 - int64(value)), n + length, nil
+		}
+	}
+	return 0, 0, ErrUnsupportedItem
+}
+
+// AppendNanotimeEpoch appends t as a tag 1 epoch-based date/time. Note that
+// float64 cannot hold every nanosecond value, so fractional values may be
+// rounded; use AppendNanotimeString or AppendNanotimeRaw to preserve them.
+func AppendNanotimeEpoch(dst []byte, t smalltime.Nanotime) []byte {
+	return appendEpoch(dst, t)
+}
+
+// AppendNanotimeString appends t as a tag 0 RFC 3339 string.
+func AppendNanotimeString(dst []byte, t smalltime.Nanotime) ([]byte, error) {
+	return appendString(dst, t)
+}
+
+// AppendNanotimeRaw appends t's raw encoding tagged with TagNanotime.
+func AppendNanotimeRaw(dst []byte, t smalltime.Nanotime) []byte {
+	return appendHead(appendHead(dst, majorTag, TagNanotime), majorUnsigned, uint64(t))
+}
+
+// DecodeNanotime decodes a tag 0, tag 1, or TagNanotime data item, returning
+// the value and the number of bytes consumed.
+func DecodeNanotime(src []byte) (smalltime.Nanotime, int, error) {
+	major, _, tag, n, err := decodeHead(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	if major != majorTag {
+		return 0, 0, ErrUnsupportedItem
+	}
+	switch tag {
+	case TagDateTimeString:
+		gotime, leapSecond, length, err := decodeString(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		t, err := smalltime.NanotimeFromTimeChecked(gotime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("smalltimecbor: %w", err)
+		}
+		if leapSecond {
+			t, _ = t.WithSecond(60)
+		}
+		return t, n + length, nil
+	case TagEpochDateTime:
+		gotime, length, err := decodeEpoch(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		t, err := smalltime.NanotimeFromTimeChecked(gotime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("smalltimecbor: %w", err)
+		}
+		return t, n + length, nil
+	case TagNanotime:
+		major, _, value, length, err := decodeHead(src[n:])
+		if err != nil {
+			return 0, 0, err
+		}
+		if major == majorUnsigned {
+			return smalltime.Nanotime(value), n + length, nil
+		}
+	}
+	return 0, 0, ErrUnsupportedItem
+}
+
+func checkConsumed(data []byte, n int, err error) error {
+	if err == nil && n != len(data) {
+		return ErrTrailingData
+	}
+	return err
+}
+
+// Smalltime is a smalltime.Smalltime that CBOR libraries can encode and decode
+// through its MarshalCBOR and UnmarshalCBOR methods.
+type Smalltime smalltime.Smalltime
+
+// MarshalCBOR encodes t with TagSmalltime, which preserves it exactly.
+func (t Smalltime) MarshalCBOR() ([]byte, error) {
+	return AppendSmalltimeRaw(nil, smalltime.Smalltime(t)), nil
+}
+
+// UnmarshalCBOR decodes a single tag 0, tag 1, or TagSmalltime data item.
+func (t *Smalltime) UnmarshalCBOR(data []byte) error {
+	value, n, err := DecodeSmalltime(data)
+	if err = checkConsumed(data, n, err); err != nil {
+		return err
+	}
+	*t = Smalltime(value)
+	return nil
+}
+
+// Nanotime is a smalltime.Nanotime that CBOR libraries can encode and decode
+// through its MarshalCBOR and UnmarshalCBOR methods.
+type Nanotime smalltime.Nanotime
+
+// MarshalCBOR encodes t with TagNanotime, which preserves it exactly.
+func (t Nanotime) MarshalCBOR() ([]byte, error) {
+	return AppendNanotimeRaw(nil, smalltime.Nanotime(t)), nil
+}
+
+// UnmarshalCBOR decodes a single tag 0, tag 1, or TagNanotime data item.
+func (t *Nanotime) UnmarshalCBOR(data []byte) error {
+	value, n, err := DecodeNanotime(data)
+	if err = checkConsumed(data, n, err); err != nil {
+		return err
+	}
+	*t = Nanotime(value)
+	return nil
+}
@@ -0,0 +1,177 @@
+package smalltimecbor
+
+import "bytes"
+import "errors"
+import "encoding/hex"
+import "testing"
+
+import "github.com/kstenerud/go-smalltime"
+
+func decodeHex(t *testing.T, str string) []byte {
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func assertEncoded(t *testing.T, actual []byte, expected string) {
+	if hex.EncodeToString(actual) != expected {
+		t.Errorf("Expected %v but got %x", expected, actual)
+	}
+}
+
+func assertDecodeSmalltime(t *testing.T, encoded string, expected smalltime.Smalltime) {
+	data := decodeHex(t, encoded)
+	actual, n, err := DecodeSmalltime(data)
+	if err != nil {
+		t.Errorf("Unexpected error decoding %v: %v", encoded, err)
+	} else if actual != expected || n != len(data) {
+		t.Errorf("Expected %v to decode to %v (%v bytes) but got %v (%v bytes)",
+			encoded, smalltime.Format(expected), len(data), smalltime.Format(actual), n)
+	}
+}
+
+// Test vectors from RFC 8949 Appendix A
+func TestSpecVectors(t *testing.T) {
+	value := smalltime.NewSmalltime(2013, 3, 21, 20, 4, 0, 0)
+	encoded, err := AppendSmalltimeString(nil, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEncoded(t, encoded, "c074323031332d30332d32315432303a30343a30305a")
+	assertDecodeSmalltime(t, "c074323031332d30332d32315432303a30343a30305a", value)
+
+	assertEncoded(t, AppendSmalltimeEpoch(nil, value), "c11a514b67b0")
+	assertDecodeSmalltime(t, "c11a514b67b0", value)
+
+	half := smalltime.NewSmalltime(2013, 3, 21, 20, 4, 0, 500000)
+	assertEncoded(t, AppendSmalltimeEpoch(nil, half), "c1fb41d452d9ec200000")
+	assertDecodeSmalltime(t, "c1fb41d452d9ec200000", half)
+}
+
+func TestEpochForms(t *testing.T) {
+	epoch := smalltime.NewSmalltime(1970, 1, 1, 0, 0, 0, 0)
+	assertEncoded(t, AppendSmalltimeEpoch(nil, epoch), "c100")
+	before := smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 0)
+	assertEncoded(t, AppendSmalltimeEpoch(nil, before), "c120")
+	assertDecodeSmalltime(t, "c120", before)
+
+	// Half and single precision floats
+	assertDecodeSmalltime(t, "c1f93e00", smalltime.NewSmalltime(1970, 1, 1, 0, 0, 1, 500000))
+	assertDecodeSmalltime(t, "c1fa3fc00000", smalltime.NewSmalltime(1970, 1, 1, 0, 0, 1, 500000))
+
+	leap := smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 0)
+	decoded, _, err := DecodeSmalltime(AppendSmalltimeEpoch(nil, leap))
+	if err != nil || decoded != smalltime.NewSmalltime(2016, 12, 31, 23, 59, 59, 999999) {
+		t.Errorf("Expected leap second to become the end of second 59 but got %v (%v)", smalltime.Format(decoded), err)
+	}
+}
+
+func TestStringForms(t *testing.T) {
+	leap := smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 250000)
+	encoded, err := AppendSmalltimeString(nil, leap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _, err := DecodeSmalltime(encoded)
+	if err != nil || actual != leap {
+		t.Errorf("Expected %v but got %v (%v)", smalltime.Format(leap), smalltime.Format(actual), err)
+	}
+
+	// 2016-12-31T18:59:60-05:00
+	assertDecodeSmalltime(t, "c07819323031362d31322d33315431383a35393a36302d30353a3030", smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 0))
+
+	if _, err := AppendSmalltimeString(nil, smalltime.NewSmalltime(10000, 1, 1, 0, 0, 0, 0)); err == nil {
+		t.Errorf("Expected year 10000 to be rejected")
+	}
+}
+
+func TestRawForms(t *testing.T) {
+	for _, value := range []smalltime.Smalltime{
+		smalltime.NewSmalltime(1985, 10, 26, 8, 22, 16, 900142),
+		smalltime.NewSmalltime(-44, 3, 15, 12, 0, 0, 0),
+		0,
+	} {
+		encoded := AppendSmalltimeRaw(nil, value)
+		if !bytes.HasPrefix(encoded, []byte{0xda, 0x73, 0x6d, 0x74, 0x00}) {
+			t.Errorf("Expected %x to start with TagSmalltime", encoded)
+		}
+		actual, n, err := DecodeSmalltime(encoded)
+		if err != nil || actual != value || n != len(encoded) {
+			t.Errorf("Expected %016x but got %016x (%v)", value, actual, err)
+		}
+	}
+	assertEncoded(t, AppendSmalltimeRaw(nil, smalltime.Smalltime(0x1f06b48590dbc2e)), "da736d74001b01f06b48590dbc2e")
+}
+
+func TestNanotime(t *testing.T) {
+	value := smalltime.NewNanotime(1985, 10, 26, 8, 22, 16, 123900142)
+	encoded := AppendNanotimeRaw(nil, value)
+	assertEncoded(t, encoded, "da6e6d74001b0fad2164076290ee")
+	actual, _, err := DecodeNanotime(encoded)
+	if err != nil || actual != value {
+		t.Errorf("Expected %016x but got %016x (%v)", value, actual, err)
+	}
+
+	encoded, err = AppendNanotimeString(nil, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _, err = DecodeNanotime(encoded)
+	if err != nil || actual != value {
+		t.Errorf("Expected %016x but got %016x (%v)", value, actual, err)
+	}
+
+	whole := smalltime.NewNanotime(2013, 3, 21, 20, 4, 0, 0)
+	assertEncoded(t, AppendNanotimeEpoch(nil, whole), "c11a514b67b0")
+	if _, _, err := DecodeNanotime(decodeHex(t, "c120")); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected 1969 to be outside of Nanotime's range but got %v", err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	for _, encoded := range []string{"", "c1", "c11a514b", "c2", "c17f", "c0", "c074323031", "c1f97e00", "c0781a"} {
+		if _, _, err := DecodeSmalltime(decodeHex(t, encoded)); err == nil {
+			t.Errorf("Expected %v to fail decoding", encoded)
+		}
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	if actual, _, err := DecodeSmalltime(decodeHex(t, "c11b00ffffffffffffff")); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected an out of range error but got %v (%v)", smalltime.Format(actual), err)
+	}
+	if actual, _, err := DecodeSmalltime(decodeHex(t, "c13b00ffffffffffffff")); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected an out of range error but got %v (%v)", smalltime.Format(actual), err)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	value := Smalltime(smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 500000))
+	encoded, err := value.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Smalltime
+	if err := decoded.UnmarshalCBOR(encoded); err != nil || decoded != value {
+		t.Errorf("Expected %016x but got %016x (%v)", value, decoded, err)
+	}
+	if err := decoded.UnmarshalCBOR(decodeHex(t, "c11a514b67b0")); err != nil || decoded != Smalltime(smalltime.NewSmalltime(2013, 3, 21, 20, 4, 0, 0)) {
+		t.Errorf("Unexpected result %016x (%v)", decoded, err)
+	}
+	if err := decoded.UnmarshalCBOR(decodeHex(t, "c11a514b67b000")); err != ErrTrailingData {
+		t.Errorf("Expected ErrTrailingData but got %v", err)
+	}
+
+	nvalue := Nanotime(smalltime.NewNanotime(1985, 10, 26, 8, 22, 16, 123900142))
+	encoded, err = nvalue.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEncoded(t, encoded, "da6e6d74001b0fad2164076290ee")
+	var ndecoded Nanotime
+	if err := ndecoded.UnmarshalCBOR(encoded); err != nil || ndecoded != nvalue {
+		t.Errorf("Expected %016x but got %016x (%v)", nvalue, ndecoded, err)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Package smalltimehttp reads and writes HTTP-date header fields (such as Date,
+Last-Modified, Expires and If-Modified-Since) as smalltime values.
+
+Headers are always written as RFC 9110 IMF-fixdates, and any of the three
+HTTP-date forms are accepted when reading.
+*/
+package smalltimehttp
+
+import (
+	"net/http"
+
+	"github.com/kstenerud/go-smalltime"
+)
+
+// GetDate returns the value of the header key as a Smalltime. A missing or
+// empty header returns the zero value (which IsZero) and no error.
+func GetDate(h http.Header, key string) (smalltime.Smalltime, error) {
+	value := h.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	return smalltime.ParseHTTPDate(value)
+}
+
+// SetDate sets the header key to t as an IMF-fixdate, replacing any existing
+// values. A zero t deletes the header instead.
+func SetDate(h http.Header, key string, t smalltime.Smalltime) {
+	if t.IsZero() {
+		h.Del(key)
+		return
+	}
+	h.Set(key, t.FormatHTTPDate())
+}
@@ -0,0 +1,43 @@
+package smalltimehttp
+
+import "net/http"
+import "testing"
+
+import "github.com/kstenerud/go-smalltime"
+
+func TestSetDate(t *testing.T) {
+	h := http.Header{}
+	SetDate(h, "Last-Modified", smalltime.NewSmalltime(1994, 11, 6, 8, 49, 37, 500000))
+	if actual := h.Get("Last-Modified"); actual != "Sun, 06 Nov 1994 08:49:37 GMT" {
+		t.Errorf("Unexpected header value %q", actual)
+	}
+	SetDate(h, "Last-Modified", 0)
+	if _, ok := h["Last-Modified"]; ok {
+		t.Errorf("Expected a zero value to delete the header")
+	}
+}
+
+func TestGetDate(t *testing.T) {
+	expected := smalltime.NewSmalltime(1994, 11, 6, 8, 49, 37, 0)
+	for _, value := range []string{
+		"Sun, 06 Nov 1994 08:49:37 GMT",
+		"Sunday, 06-Nov-94 08:49:37 GMT",
+		"Sun Nov  6 08:49:37 1994",
+	} {
+		h := http.Header{}
+		h.Set("If-Modified-Since", value)
+		actual, err := GetDate(h, "If-Modified-Since")
+		if err != nil || actual != expected {
+			t.Errorf("Expected %q to be %v but got %v (error %v)", value, smalltime.Format(expected), smalltime.Format(actual), err)
+		}
+	}
+
+	h := http.Header{}
+	if actual, err := GetDate(h, "Expires"); err != nil || !actual.IsZero() {
+		t.Errorf("Expected a missing header to be zero but got %v (error %v)", smalltime.Format(actual), err)
+	}
+	h.Set("Expires", "0")
+	if _, err := GetDate(h, "Expires"); err == nil {
+		t.Errorf("Expected an invalid date to fail")
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Package smalltimemsgpack encodes and decodes smalltime & nanotime values as the
+MessagePack timestamp extension type (-1), choosing the smallest of the 32, 64
+and 96-bit forms that can hold the value.
+
+The Append and Decode functions work with the complete extension (header and
+payload), while the Payload functions work with just the payload, for use with
+MessagePack libraries that handle extension headers themselves.
+
+Since the timestamp extension has no leap seconds, second 60 is converted to
+the last instant of second 59.
+
+The Smalltime and Nanotime types can be registered as the timestamp extension
+with MessagePack libraries. They implement the Extension interface of
+github.com/tinylib/msgp:
+
+	msgp.RegisterExtension(smalltimemsgpack.ExtTypeTimestamp, func() msgp.Extension { return new(smalltimemsgpack.Smalltime) })
+
+and the payload marshaling methods of github.com/vmihailenco/msgpack:
+
+	msgpack.RegisterExt(smalltimemsgpack.ExtTypeTimestamp, (*smalltimemsgpack.Smalltime)(nil))
+*/
+package smalltimemsgpack
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kstenerud/go-smalltime"
+)
+
+const ExtTypeTimestamp int8 = -1
+
+const (
+	typeFixext4 = 0xd6
+	typeFixext8 = 0xd7
+	typeExt8    = 0xc7
+)
+
+var ErrUnexpectedEnd = errors.New("smalltimemsgpack: unexpected end of data")
+var ErrNotTimestamp = errors.New("smalltimemsgpack: not a timestamp extension")
+
+func appendBigEndian(dst []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		dst = append(dst, byte(value>>(8*i)))
+	}
+	return dst
+}
+
+func readBigEndian(src []byte) (value uint64) {
+	for _, b := range src {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+func asTimeWithoutLeapSecond(t smalltime.Timestamp) time.Time {
+	if t.Second() == 60 {
+		return time.Date(t.Year(), time.Month(t.Month()), t.Day(), t.Hour(),
+			t.Minute(), 59, 999999999, time.UTC)
+	}
+	return t.AsTime()
+}
+
+func appendPayload(dst []byte, t smalltime.Timestamp) []byte {
+	gotime := asTimeWithoutLeapSecond(t)
+	seconds := gotime.Unix()
+	nanos := uint64(gotime.Nanosecond())
+	if seconds>>34 == 0 {
+		if nanos == 0 && seconds>>32 == 0 {
+			return appendBigEndian(dst, uint64(seconds), 4)
+		}
+		return appendBigEndian(dst, nanos<<34|uint64(seconds), 8)
+	}
+	return appendBigEndian(appendBigEndian(dst, nanos, 4), uint64(seconds), 8)
+}
+
+func decodePayload(payload []byte) (time.Time, error) {
+	var seconds, nanos int64
+	switch len(payload) {
+	case 4:
+		seconds = int64(readBigEndian(payload))
+	case 8:
+		value := readBigEndian(payload)
+		nanos = int64(value >> 34)
+		seconds = int64(value & (1<<34 - 1))
+	case 12:
+		nanos = int64(readBigEndian(payload[:4]))
+		seconds = int64(readBigEndian(payload[4:]))
+	default:
+		return time.Time{}, fmt.Errorf("smalltimemsgpack: invalid timestamp payload length %v", len(payload))
+	}
+	if nanos > 999999999 {
+		return time.Time{}, fmt.Errorf("smalltimemsgpack: nanoseconds %v out of range", nanos)
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+func appendExt(dst []byte, t smalltime.Timestamp) []byte {
+	var buffer [12]byte
+	payload := appendPayload(buffer[:0], t)
+	switch len(payload) {
+	case 4:
+		dst = append(dst, typeFixext4, 0xff)
+	case 8:
+		dst = append(dst, typeFixext8, 0xff)
+	default:
+		dst = append(dst, typeExt8, byte(len(payload)), 0xff)
+	}
+	return append(dst, payload...)
+}
+
+// Returns the payload and the total number of bytes consumed.
+func decodeExt(src []byte) ([]byte, int, error) {
+	if len(src) < 1 {
+		return nil, 0, ErrUnexpectedEnd
+	}
+	var headerLength, payloadLength int
+	switch src[0] {
+	case typeFixext4:
+		headerLength, payloadLength = 2, 4
+	case typeFixext8:
+		headerLength, payloadLength = 2, 8
+	case typeExt8:
+		if len(src) < 2 {
+			return nil, 0, ErrUnexpectedEnd
+		}
+		headerLength, payloadLength = 3, int(src[1])
+	default:
+		return nil, 0, ErrNotTimestamp
+	}
+	if len(src) < headerLength {
+		return nil, 0, ErrUnexpectedEnd
+	}
+	if int8(src[headerLength-1]) != ExtTypeTimestamp {
+		return nil, 0, ErrNotTimestamp
+	}
+	end := headerLength + payloadLength
+	if len(src) < end {
+		return nil, 0, ErrUnexpectedEnd
+	}
+	return src[headerLength:end], end, nil
+}
+
+// AppendSmalltime appends t as a timestamp extension.
+func AppendSmalltime(dst []byte, t smalltime.Smalltime) []byte {
+	return appendExt(dst, t)
+}
+
+// DecodeSmalltime decodes a timestamp extension, returning the value and the
+// number of bytes consumed.
+func DecodeSmalltime(src []byte) (smalltime.Smalltime, int, error) {
+	payload, n, err := decodeExt(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	t, err := SmalltimeFromPayload(payload)
+	return t, n, err
+}
+
+// AppendSmalltimePayload appends the timestamp extension payload for t.
+func AppendSmalltimePayload(dst []byte, t smalltime.Smalltime) []byte {
+	return appendPayload(dst, t)
+}
+
+// SmalltimeFromPayload decodes a timestamp extension payload, returning an
+// error matching smalltime.ErrOutOfRange if it's outside of Smalltime's range.
+func SmalltimeFromPayload(payload []byte) (smalltime.Smalltime, error) {
+	gotime, err := decodePayload(payload)
+	if err != nil {
+		return 0, err
+	}
+	t, err := smalltime.SmalltimeFromTimeChecked(gotime)
+	if err != nil {
+		return 0, fmt.Errorf("smalltimemsgpack: %w", err)
+	}
+	return t, nil
+}
+
+// AppendNanotime appends t as a timestamp extension.
+func AppendNanotime(dst []byte, t smalltime.Nanotime) []byte {
+	return appendExt(dst, t)
+}
+
+// DecodeNanotime decodes a timestamp extension, returning the value and the
+// number of bytes consumed.
+func DecodeNanotime(src []byte) (smalltime.Nanotime, int, error) {
+	payload, n, err := decodeExt(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	t, err := NanotimeFromPayload(payload)
+	return t, n, err
+}
+
+// AppendNanotimePayload appends the timestamp extension payload for t.
+func AppendNanotimePayload(dst []byte, t smalltime.Nanotime) []byte {
+	return appendPayload(dst, t)
+}
+
+// NanotimeFromPayload decodes a timestamp extension payload, returning an
+// error matching smalltime.ErrOutOfRange if it's outside of Nanotime's range
+// (1970 - 2225).
+func NanotimeFromPayload(payload []byte) (smalltime.Nanotime, error) {
+	gotime, err := decodePayload(payload)
+	if err != nil {
+		return 0, err
+	}
+	t, err := smalltime.NanotimeFromTimeChecked(gotime)
+	if err != nil {
+		return 0, fmt.Errorf("smalltimemsgpack: %w", err)
+	}
+	return t, nil
+}
+
+// Smalltime is a smalltime.Smalltime that MessagePack libraries can register
+// as the timestamp extension (see the package documentation).
+type Smalltime smalltime.Smalltime
+
+func (t *Smalltime) ExtensionType() int8 {
+	return ExtTypeTimestamp
+}
+
+// Len returns the length of the extension payload.
+func (t *Smalltime) Len() int {
+	var buffer [12]byte
+	return len(AppendSmalltimePayload(buffer[:0], smalltime.Smalltime(*t)))
+}
+
+// MarshalBinaryTo writes the extension payload to b, which must be Len() bytes.
+func (t *Smalltime) MarshalBinaryTo(b []byte) error {
+	copy(b, AppendSmalltimePayload(nil, smalltime.Smalltime(*t)))
+	return nil
+}
+
+// UnmarshalBinary decodes an extension payload.
+func (t *Smalltime) UnmarshalBinary(b []byte) error {
+	value, err := SmalltimeFromPayload(b)
+	if err != nil {
+		return err
+	}
+	*t = Smalltime(value)
+	return nil
+}
+
+// MarshalMsgpack returns the extension payload.
+func (t *Smalltime) MarshalMsgpack() ([]byte, error) {
+	return AppendSmalltimePayload(nil, smalltime.Smalltime(*t)), nil
+}
+
+// UnmarshalMsgpack decodes an extension payload.
+func (t *Smalltime) UnmarshalMsgpack(b []byte) error {
+	return t.UnmarshalBinary(b)
+}
+
+// Nanotime is a smalltime.Nanotime that MessagePack libraries can register
+// as the timestamp extension (see the package documentation).
+type Nanotime smalltime.Nanotime
+
+func (t *Nanotime) ExtensionType() int8 {
+	return ExtTypeTimestamp
+}
+
+// Len returns the length of the extension payload.
+func (t *Nanotime) Len() int {
+	var buffer [12]byte
+	return len(AppendNanotimePayload(buffer[:0], smalltime.Nanotime(*t)))
+}
+
+// MarshalBinaryTo writes the extension payload to b, which must be Len() bytes.
+func (t *Nanotime) MarshalBinaryTo(b []byte) error {
+	copy(b, AppendNanotimePayload(nil, smalltime.Nanotime(*t)))
+	return nil
+}
+
+// UnmarshalBinary decodes an extension payload.
+func (t *Nanotime) UnmarshalBinary(b []byte) error {
+	value, err := NanotimeFromPayload(b)
+	if err != nil {
+		return err
+	}
+	*t = Nanotime(value)
+	return nil
+}
+
+// MarshalMsgpack returns the extension payload.
+func (t *Nanotime) MarshalMsgpack() ([]byte, error) {
+	return AppendNanotimePayload(nil, smalltime.Nanotime(*t)), nil
+}
+
+// UnmarshalMsgpack decodes an extension payload.
+func (t *Nanotime) UnmarshalMsgpack(b []byte) error {
+	return t.UnmarshalBinary(b)
+}
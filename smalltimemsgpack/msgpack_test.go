@@ -0,0 +1,131 @@
+package smalltimemsgpack
+
+import "encoding/hex"
+import "errors"
+import "testing"
+
+import "github.com/kstenerud/go-smalltime"
+
+func assertRoundTrip(t *testing.T, value smalltime.Smalltime, expected string) {
+	encoded := AppendSmalltime(nil, value)
+	if hex.EncodeToString(encoded) != expected {
+		t.Errorf("Expected %v to encode to %v but got %x", smalltime.Format(value), expected, encoded)
+	}
+	data, err := hex.DecodeString(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, n, err := DecodeSmalltime(data)
+	if err != nil {
+		t.Errorf("Unexpected error decoding %v: %v", expected, err)
+	} else if actual != value || n != len(data) {
+		t.Errorf("Expected %v to decode to %v but got %v", expected, smalltime.Format(value), smalltime.Format(actual))
+	}
+}
+
+// Test vectors from the msgpack-test-suite timestamp set
+func TestSpecVectors(t *testing.T) {
+	assertRoundTrip(t, smalltime.NewSmalltime(1970, 1, 1, 0, 0, 0, 0), "d6ff00000000")
+	assertRoundTrip(t, smalltime.NewSmalltime(2106, 2, 7, 6, 28, 15, 0), "d6ffffffffff")
+	assertRoundTrip(t, smalltime.NewSmalltime(2106, 2, 7, 6, 28, 16, 0), "d7ff0000000100000000")
+	assertRoundTrip(t, smalltime.NewSmalltime(2514, 5, 30, 1, 53, 3, 0), "d7ff00000003ffffffff")
+	assertRoundTrip(t, smalltime.NewSmalltime(2514, 5, 30, 1, 53, 4, 0), "c70cff000000000000000400000000")
+	assertRoundTrip(t, smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 0), "c70cff00000000ffffffffffffffff")
+	assertRoundTrip(t, smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 999999), "c70cff3b9ac618ffffffffffffffff")
+	assertRoundTrip(t, smalltime.NewSmalltime(1970, 1, 1, 0, 0, 0, 1), "d7ff00000fa000000000")
+}
+
+func TestNanotime(t *testing.T) {
+	for _, vector := range []struct {
+		value    smalltime.Nanotime
+		expected string
+	}{
+		{smalltime.NewNanotime(1970, 1, 1, 0, 0, 0, 1), "d7ff0000000400000000"},
+		{smalltime.NewNanotime(2106, 2, 7, 6, 28, 15, 0), "d6ffffffffff"},
+		{smalltime.NewNanotime(2225, 12, 31, 23, 59, 59, 999999999), "d7ffee6b27fde1853cff"},
+	} {
+		encoded := AppendNanotime(nil, vector.value)
+		if hex.EncodeToString(encoded) != vector.expected {
+			t.Errorf("Expected %v to encode to %v but got %x", smalltime.Format(vector.value), vector.expected, encoded)
+		}
+		actual, _, err := DecodeNanotime(encoded)
+		if err != nil || actual != vector.value {
+			t.Errorf("Expected %v but got %v (%v)", smalltime.Format(vector.value), smalltime.Format(actual), err)
+		}
+	}
+
+	before, _ := hex.DecodeString("c70cff3b9ac9ffffffffffffffffff")
+	if _, _, err := DecodeNanotime(before); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected 1969 to be outside of Nanotime's range but got %v", err)
+	}
+}
+
+func TestPayload(t *testing.T) {
+	value := smalltime.NewSmalltime(2019, 5, 20, 14, 22, 1, 500)
+	payload := AppendSmalltimePayload(nil, value)
+	if len(payload) != 8 {
+		t.Errorf("Expected an 8 byte payload but got %x", payload)
+	}
+	actual, err := SmalltimeFromPayload(payload)
+	if err != nil || actual != value {
+		t.Errorf("Expected %v but got %v (%v)", smalltime.Format(value), smalltime.Format(actual), err)
+	}
+	if _, err := SmalltimeFromPayload(payload[:5]); err == nil {
+		t.Errorf("Expected a 5 byte payload to be rejected")
+	}
+}
+
+func TestLeapSecond(t *testing.T) {
+	leap := smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 0)
+	actual, _, err := DecodeSmalltime(AppendSmalltime(nil, leap))
+	if err != nil || actual != smalltime.NewSmalltime(2016, 12, 31, 23, 59, 59, 999999) {
+		t.Errorf("Expected leap second to become the end of second 59 but got %v (%v)", smalltime.Format(actual), err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	for _, encoded := range []string{"", "d6", "d6fe00000000", "d6ff0000", "c70cff00", "c705ff0000000000", "c0", "d7ffffffffff00000000"} {
+		data, _ := hex.DecodeString(encoded)
+		if _, _, err := DecodeSmalltime(data); err == nil {
+			t.Errorf("Expected %v to fail decoding", encoded)
+		}
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	data, _ := hex.DecodeString("c70cff0000000000ffffffffffffff")
+	if actual, _, err := DecodeSmalltime(data); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected an out of range error but got %v (%v)", smalltime.Format(actual), err)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	value := Smalltime(smalltime.NewSmalltime(2019, 5, 20, 14, 22, 1, 500))
+	if value.ExtensionType() != -1 || value.Len() != 8 {
+		t.Errorf("Unexpected extension type %v and length %v", value.ExtensionType(), value.Len())
+	}
+	payload := make([]byte, value.Len())
+	if err := value.MarshalBinaryTo(payload); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Smalltime
+	if err := decoded.UnmarshalBinary(payload); err != nil || decoded != value {
+		t.Errorf("Expected %016x but got %016x (%v)", value, decoded, err)
+	}
+	if payload, err := value.MarshalMsgpack(); err != nil || hex.EncodeToString(payload) != hex.EncodeToString(AppendSmalltimePayload(nil, smalltime.Smalltime(value))) {
+		t.Errorf("Unexpected payload %x (%v)", payload, err)
+	}
+
+	nvalue := Nanotime(smalltime.NewNanotime(2225, 12, 31, 23, 59, 59, 999999999))
+	payload, err := nvalue.MarshalMsgpack()
+	if err != nil || len(payload) != nvalue.Len() {
+		t.Errorf("Unexpected payload %x (%v)", payload, err)
+	}
+	var ndecoded Nanotime
+	if err := ndecoded.UnmarshalMsgpack(payload); err != nil || ndecoded != nvalue {
+		t.Errorf("Expected %016x but got %016x (%v)", nvalue, ndecoded, err)
+	}
+	if err := ndecoded.UnmarshalMsgpack([]byte{0xff, 0xff, 0xff, 0xff, 0xff}); err == nil {
+		t.Errorf("Expected a 5 byte payload to be rejected")
+	}
+}
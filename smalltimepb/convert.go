@@ -0,0 +1,183 @@
+/*
+Package smalltimepb converts between smalltime & nanotime values and the
+google.protobuf.Timestamp and google.protobuf.Duration well-known types.
+
+It also provides the Smalltime and Nanotime messages (see smalltime.proto),
+which carry the raw 64-bit value for services that want to use the formats
+end-to-end.
+
+google.protobuf.Timestamp has no leap seconds, so a value at second 60 is
+converted to the last representable instant of second 59. Timestamps outside
+of 0001-01-01T00:00:00Z to 9999-12-31T23:59:59.999999999Z are rejected, as
+required by the protobuf specification.
+*/
+package smalltimepb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kstenerud/go-smalltime"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func asTimeWithoutLeapSecond(t smalltime.Timestamp) time.Time {
+	if t.Second() == 60 {
+		return time.Date(t.Year(), time.Month(t.Month()), t.Day(), t.Hour(),
+			t.Minute(), 59, 999999999, time.UTC)
+	}
+	return t.AsTime()
+}
+
+func toTimestamp(t smalltime.Timestamp) (*timestamppb.Timestamp, error) {
+	ts := timestamppb.New(asTimeWithoutLeapSecond(t))
+	if err := ts.CheckValid(); err != nil {
+		return nil, fmt.Errorf("smalltimepb: %v cannot be represented as a Timestamp: %w", smalltime.Format(t), err)
+	}
+	return ts, nil
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) (time.Time, error) {
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return ts.AsTime(), nil
+}
+
+// SmalltimeToTimestamp converts a Smalltime to a Timestamp, truncating the
+// result to the last nanosecond of second 59 if it is a leap second. Invalid
+// values (see Smalltime.Validate) are rejected rather than normalized.
+func SmalltimeToTimestamp(t smalltime.Smalltime) (*timestamppb.Timestamp, error) {
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("smalltimepb: %w", err)
+	}
+	if t.Second() == 60 {
+		// Smalltime resolution is microseconds
+		return toTimestamp(smalltime.NewSmalltime(t.Year(), t.Month(), t.Day(),
+			t.Hour(), t.Minute(), 59, 999999))
+	}
+	return toTimestamp(t)
+}
+
+func SmalltimeFromTimestamp(ts *timestamppb.Timestamp) (smalltime.Smalltime, error) {
+	t, err := fromTimestamp(ts)
+	if err != nil {
+		return 0, err
+	}
+	return smalltime.SmalltimeFromTime(t), nil
+}
+
+// NanotimeToTimestamp converts a Nanotime to a Timestamp, truncating the
+// result to the last nanosecond of second 59 if it is a leap second. Invalid
+// values (see Nanotime.Validate) are rejected rather than normalized.
+func NanotimeToTimestamp(t smalltime.Nanotime) (*timestamppb.Timestamp, error) {
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return toTimestamp(t)
+}
+
+// NanotimeFromTimestamp converts a Timestamp to a Nanotime, returning an error
+// matching smalltime.ErrOutOfRange if it falls outside of Nanotime's range
+// (1970 - 2225).
+func NanotimeFromTimestamp(ts *timestamppb.Timestamp) (smalltime.Nanotime, error) {
+	t, err := fromTimestamp(ts)
+	if err != nil {
+		return 0, err
+	}
+	value, err := smalltime.NanotimeFromTimeChecked(t)
+	if err != nil {
+		return 0, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return value, nil
+}
+
+// DurationBetweenSmalltimes returns the elapsed time from `from` to `to`,
+// returning an error if it exceeds a Duration's range of about 10000 years.
+// Leap seconds are treated as the first second of the following minute.
+func DurationBetweenSmalltimes(from, to smalltime.Smalltime) (*durationpb.Duration, error) {
+	return durationBetween(from.AsTime(), to.AsTime())
+}
+
+// DurationBetweenNanotimes returns the elapsed time from `from` to `to`. Leap
+// seconds are treated as the first second of the following minute.
+func DurationBetweenNanotimes(from, to smalltime.Nanotime) (*durationpb.Duration, error) {
+	return durationBetween(from.AsTime(), to.AsTime())
+}
+
+// Unlike time.Time.Sub, this doesn't saturate at time.Duration's ~292 year
+// range, which is well within Smalltime's.
+func durationBetween(from, to time.Time) (*durationpb.Duration, error) {
+	seconds := to.Unix() - from.Unix()
+	nanos := int32(to.Nanosecond() - from.Nanosecond())
+	if seconds > 0 && nanos < 0 {
+		seconds--
+		nanos += 1000000000
+	} else if seconds < 0 && nanos > 0 {
+		seconds++
+		nanos -= 1000000000
+	}
+	d := &durationpb.Duration{Seconds: seconds, Nanos: nanos}
+	if err := d.CheckValid(); err != nil {
+		return nil, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return d, nil
+}
+
+// As with durationBetween, this works in Unix seconds rather than
+// time.Duration, since a Duration can span up to 10000 years.
+func addDuration(t time.Time, d *durationpb.Duration) (time.Time, error) {
+	if err := d.CheckValid(); err != nil {
+		return time.Time{}, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return time.Unix(t.Unix()+d.Seconds, int64(t.Nanosecond())+int64(d.Nanos)).UTC(), nil
+}
+
+// AddDurationToSmalltime adds a Duration to a Smalltime, returning an error
+// matching smalltime.ErrOutOfRange if the result falls outside of Smalltime's
+// range. The result is truncated to the microsecond.
+func AddDurationToSmalltime(t smalltime.Smalltime, d *durationpb.Duration) (smalltime.Smalltime, error) {
+	result, err := addDuration(t.AsTime(), d)
+	if err != nil {
+		return 0, err
+	}
+	value, err := smalltime.SmalltimeFromTimeChecked(result)
+	if err != nil {
+		return 0, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return value, nil
+}
+
+// AddDurationToNanotime adds a Duration to a Nanotime, returning an error
+// matching smalltime.ErrOutOfRange if the result falls outside of Nanotime's
+// range (1970 - 2225).
+func AddDurationToNanotime(t smalltime.Nanotime, d *durationpb.Duration) (smalltime.Nanotime, error) {
+	result, err := addDuration(t.AsTime(), d)
+	if err != nil {
+		return 0, err
+	}
+	value, err := smalltime.NanotimeFromTimeChecked(result)
+	if err != nil {
+		return 0, fmt.Errorf("smalltimepb: %w", err)
+	}
+	return value, nil
+}
+
+// NewSmalltime wraps a raw Smalltime value in a message.
+func NewSmalltime(t smalltime.Smalltime) *Smalltime {
+	return &Smalltime{Value: int64(t)}
+}
+
+func (x *Smalltime) AsSmalltime() smalltime.Smalltime {
+	return smalltime.Smalltime(x.GetValue())
+}
+
+// NewNanotime wraps a raw Nanotime value in a message.
+func NewNanotime(t smalltime.Nanotime) *Nanotime {
+	return &Nanotime{Value: uint64(t)}
+}
+
+func (x *Nanotime) AsNanotime() smalltime.Nanotime {
+	return smalltime.Nanotime(x.GetValue())
+}
@@ -0,0 +1,185 @@
+package smalltimepb
+
+import "errors"
+import "testing"
+import "time"
+
+import "github.com/kstenerud/go-smalltime"
+import "google.golang.org/protobuf/proto"
+import "google.golang.org/protobuf/types/known/durationpb"
+import "google.golang.org/protobuf/types/known/timestamppb"
+
+func assertSmalltimeTimestamp(t *testing.T, value smalltime.Smalltime, seconds int64, nanos int32) {
+	ts, err := SmalltimeToTimestamp(value)
+	if err != nil {
+		t.Errorf("Unexpected error converting %v: %v", smalltime.Format(value), err)
+		return
+	}
+	if ts.Seconds != seconds || ts.Nanos != nanos {
+		t.Errorf("Expected %v to convert to %v.%09d but got %v.%09d", smalltime.Format(value), seconds, nanos, ts.Seconds, ts.Nanos)
+	}
+	if value.Second() == 60 {
+		return
+	}
+	back, err := SmalltimeFromTimestamp(ts)
+	if err != nil || back != value {
+		t.Errorf("Expected %v.%09d to convert back to %v but got %v (%v)", seconds, nanos, smalltime.Format(value), smalltime.Format(back), err)
+	}
+}
+
+func TestSmalltimeTimestamp(t *testing.T) {
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(1970, 1, 1, 0, 0, 0, 0), 0, 0)
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(1999, 2, 15, 12, 8, 45, 9122), 919080525, 9122000)
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(1969, 12, 31, 23, 59, 59, 500000), -1, 500000000)
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(1, 1, 1, 0, 0, 0, 0), -62135596800, 0)
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(9999, 12, 31, 23, 59, 59, 999999), 253402300799, 999999000)
+	assertSmalltimeTimestamp(t, smalltime.NewSmalltime(2016, 12, 31, 23, 59, 60, 500000), 1483228799, 999999000)
+}
+
+func TestSmalltimeTimestampRange(t *testing.T) {
+	for _, value := range []smalltime.Smalltime{
+		smalltime.NewSmalltime(0, 12, 31, 23, 59, 59, 999999),
+		smalltime.NewSmalltime(10000, 1, 1, 0, 0, 0, 0),
+		smalltime.NewSmalltime(-5000, 1, 1, 0, 0, 0, 0),
+	} {
+		if _, err := SmalltimeToTimestamp(value); err == nil {
+			t.Errorf("Expected %v to be out of range", smalltime.Format(value))
+		}
+	}
+	if _, err := SmalltimeFromTimestamp(&timestamppb.Timestamp{Seconds: 253402300800}); err == nil {
+		t.Errorf("Expected an invalid Timestamp to be rejected")
+	}
+	if _, err := SmalltimeFromTimestamp(&timestamppb.Timestamp{Nanos: -1}); err == nil {
+		t.Errorf("Expected an invalid Timestamp to be rejected")
+	}
+}
+
+func TestInvalidFieldsTimestamp(t *testing.T) {
+	if ts, err := SmalltimeToTimestamp(smalltime.NewSmalltime(2000, 13, 1, 0, 0, 0, 0)); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected month 13 to be rejected but got %v (%v)", ts, err)
+	}
+	if ts, err := SmalltimeToTimestamp(smalltime.NewSmalltime(2001, 2, 29, 0, 0, 0, 0)); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected Feb 29 2001 to be rejected but got %v (%v)", ts, err)
+	}
+	if ts, err := NanotimeToTimestamp(smalltime.NewNanotime(2000, 1, 1, 24, 0, 0, 0)); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected hour 24 to be rejected but got %v (%v)", ts, err)
+	}
+}
+
+func TestNanotimeTimestamp(t *testing.T) {
+	value := smalltime.NewNanotime(1999, 2, 15, 12, 8, 45, 10159122)
+	ts, err := NanotimeToTimestamp(value)
+	if err != nil || ts.Seconds != 919080525 || ts.Nanos != 10159122 {
+		t.Errorf("Unexpected conversion of %v: %v (%v)", smalltime.Format(value), ts, err)
+	}
+	back, err := NanotimeFromTimestamp(ts)
+	if err != nil || back != value {
+		t.Errorf("Expected %v to convert back but got %v (%v)", smalltime.Format(value), smalltime.Format(back), err)
+	}
+
+	leap := smalltime.NewNanotime(2016, 12, 31, 23, 59, 60, 0)
+	ts, err = NanotimeToTimestamp(leap)
+	if err != nil || ts.Seconds != 1483228799 || ts.Nanos != 999999999 {
+		t.Errorf("Unexpected conversion of %v: %v (%v)", smalltime.Format(leap), ts, err)
+	}
+
+	if _, err := NanotimeFromTimestamp(&timestamppb.Timestamp{Seconds: -1}); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected 1969 to be outside of Nanotime's range but got %v", err)
+	}
+	if _, err := NanotimeFromTimestamp(timestamppb.New(time.Date(2226, 1, 1, 0, 0, 0, 0, time.UTC))); err == nil {
+		t.Errorf("Expected 2226 to be outside of Nanotime's range")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	from := smalltime.NewSmalltime(2000, 1, 1, 0, 0, 0, 750000)
+	to := smalltime.NewSmalltime(2000, 1, 2, 0, 0, 1, 250000)
+	d, err := DurationBetweenSmalltimes(from, to)
+	if err != nil || d.Seconds != 86400 || d.Nanos != 500000000 {
+		t.Errorf("Expected 86400.5s but got %v (%v)", d, err)
+	}
+	d, err = DurationBetweenSmalltimes(to, from)
+	if err != nil || d.Seconds != -86400 || d.Nanos != -500000000 {
+		t.Errorf("Expected -86400.5s but got %v (%v)", d, err)
+	}
+	result, err := AddDurationToSmalltime(to, d)
+	if err != nil || result != from {
+		t.Errorf("Expected %v but got %v (%v)", smalltime.Format(from), smalltime.Format(result), err)
+	}
+
+	// Durations are limited to about 10000 years
+	ancient := smalltime.NewSmalltime(-7000, 1, 1, 0, 0, 0, 0)
+	if d, err := DurationBetweenSmalltimes(ancient, from); err != nil || d.Seconds <= 0 {
+		t.Errorf("Expected a positive duration but got %v (%v)", d, err)
+	}
+	if d, err := DurationBetweenSmalltimes(smalltime.NewSmalltime(-9000, 1, 1, 0, 0, 0, 0), from); err == nil {
+		t.Errorf("Expected a span beyond 10000 years to fail but got %v", d)
+	}
+
+	nfrom := smalltime.NewNanotime(2000, 1, 1, 0, 0, 0, 1)
+	nto := smalltime.NewNanotime(2000, 1, 1, 0, 0, 0, 0)
+	d, err = DurationBetweenNanotimes(nfrom, nto)
+	if err != nil || d.Seconds != 0 || d.Nanos != -1 {
+		t.Errorf("Expected -1ns but got %v (%v)", d, err)
+	}
+	nresult, err := AddDurationToNanotime(nto, &durationpb.Duration{Nanos: 1})
+	if err != nil || nresult != nfrom {
+		t.Errorf("Expected %v but got %v (%v)", smalltime.Format(nfrom), smalltime.Format(nresult), err)
+	}
+	if _, err := AddDurationToNanotime(nto, &durationpb.Duration{Seconds: -946684801}); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected an out of range error but got %v", err)
+	}
+	if _, err := AddDurationToSmalltime(from, &durationpb.Duration{Seconds: 1, Nanos: -1}); err == nil {
+		t.Errorf("Expected an invalid duration error")
+	}
+}
+
+// Durations beyond time.Duration's ~292 year range must not wrap.
+func TestLongDuration(t *testing.T) {
+	from := smalltime.NewSmalltime(1000, 1, 1, 0, 0, 0, 0)
+	d := &durationpb.Duration{Seconds: 1e10}
+	result, err := AddDurationToSmalltime(from, d)
+	expected := smalltime.NewSmalltime(1316, 11, 20, 17, 46, 40, 0)
+	if err != nil || result != expected {
+		t.Errorf("Expected %v but got %v (%v)", smalltime.Format(expected), smalltime.Format(result), err)
+	}
+	if back, err := DurationBetweenSmalltimes(from, result); err != nil || back.Seconds != d.Seconds || back.Nanos != 0 {
+		t.Errorf("Expected %v but got %v (%v)", d, back, err)
+	}
+
+	maxDuration := &durationpb.Duration{Seconds: 315576000000, Nanos: 999999999}
+	if _, err := AddDurationToSmalltime(smalltime.NewSmalltime(131000, 1, 1, 0, 0, 0, 0), maxDuration); !errors.Is(err, smalltime.ErrOutOfRange) {
+		t.Errorf("Expected an out of range error but got %v", err)
+	}
+	if _, err := AddDurationToNanotime(smalltime.MinNanotime, &durationpb.Duration{Seconds: 1e10}); err == nil {
+		t.Errorf("Expected an out of range error")
+	}
+}
+
+func TestRawMessages(t *testing.T) {
+	value := smalltime.NewSmalltime(-44, 3, 15, 12, 0, 0, 0)
+	encoded, err := proto.Marshal(NewSmalltime(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Smalltime
+	if err := proto.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.AsSmalltime() != value {
+		t.Errorf("Expected %016x but got %016x", value, decoded.AsSmalltime())
+	}
+
+	nvalue := smalltime.NewNanotime(2225, 12, 31, 23, 59, 60, 999999999)
+	encoded, err = proto.Marshal(NewNanotime(nvalue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ndecoded Nanotime
+	if err := proto.Unmarshal(encoded, &ndecoded); err != nil {
+		t.Fatal(err)
+	}
+	if ndecoded.AsNanotime() != nvalue {
+		t.Errorf("Expected %016x but got %016x", nvalue, ndecoded.AsNanotime())
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/kstenerud/go-smalltime/smalltimepb
+
+go 1.23
+
+require github.com/kstenerud/go-smalltime v0.0.0
+
+require google.golang.org/protobuf v1.36.12
+
+replace github.com/kstenerud/go-smalltime => ../
@@ -0,0 +1,174 @@
+// Raw smalltime & nanotime values, for services that want to use the formats
+// end-to-end rather than converting to google.protobuf.Timestamp.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: smalltime.proto
+
+package smalltimepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A smalltime value (signed 64-bit, microsecond resolution).
+type Smalltime struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int64                  `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Smalltime) Reset() {
+	*x = Smalltime{}
+	mi := &file_smalltime_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Smalltime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Smalltime) ProtoMessage() {}
+
+func (x *Smalltime) ProtoReflect() protoreflect.Message {
+	mi := &file_smalltime_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Smalltime.ProtoReflect.Descriptor instead.
+func (*Smalltime) Descriptor() ([]byte, []int) {
+	return file_smalltime_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Smalltime) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// A nanotime value (unsigned 64-bit, nanosecond resolution, 1970 - 2225).
+type Nanotime struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         uint64                 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nanotime) Reset() {
+	*x = Nanotime{}
+	mi := &file_smalltime_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nanotime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nanotime) ProtoMessage() {}
+
+func (x *Nanotime) ProtoReflect() protoreflect.Message {
+	mi := &file_smalltime_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nanotime.ProtoReflect.Descriptor instead.
+func (*Nanotime) Descriptor() ([]byte, []int) {
+	return file_smalltime_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Nanotime) GetValue() uint64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_smalltime_proto protoreflect.FileDescriptor
+
+const file_smalltime_proto_rawDesc = "" +
+	"\n" +
+	"\x0fsmalltime.proto\x12\tsmalltime\"!\n" +
+	"\tSmalltime\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x10R\x05value\" \n" +
+	"\bNanotime\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x06R\x05valueB/Z-github.com/kstenerud/go-smalltime/smalltimepbb\x06proto3"
+
+var (
+	file_smalltime_proto_rawDescOnce sync.Once
+	file_smalltime_proto_rawDescData []byte
+)
+
+func file_smalltime_proto_rawDescGZIP() []byte {
+	file_smalltime_proto_rawDescOnce.Do(func() {
+		file_smalltime_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_smalltime_proto_rawDesc), len(file_smalltime_proto_rawDesc)))
+	})
+	return file_smalltime_proto_rawDescData
+}
+
+var file_smalltime_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_smalltime_proto_goTypes = []any{
+	(*Smalltime)(nil), // 0: smalltime.Smalltime
+	(*Nanotime)(nil),  // 1: smalltime.Nanotime
+}
+var file_smalltime_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_smalltime_proto_init() }
+func file_smalltime_proto_init() {
+	if File_smalltime_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_smalltime_proto_rawDesc), len(file_smalltime_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_smalltime_proto_goTypes,
+		DependencyIndexes: file_smalltime_proto_depIdxs,
+		MessageInfos:      file_smalltime_proto_msgTypes,
+	}.Build()
+	File_smalltime_proto = out.File
+	file_smalltime_proto_goTypes = nil
+	file_smalltime_proto_depIdxs = nil
+}
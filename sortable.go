@@ -0,0 +1,78 @@
+package smalltime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The sortable encoding writes the 64-bit value as 13 Crockford base32
+// characters, most significant first. The alphabet is in ASCII order, so the
+// lexicographic order of the strings matches the numeric order of the values.
+// Smalltime's sign bit is flipped so that negative years sort first.
+
+const sortableAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const sortableLength = 13
+
+var ErrInvalidSortable = errors.New("smalltime: invalid sortable encoding")
+
+var sortableDecodeTable = func() (table [256]byte) {
+	for i := range table {
+		table[i] = 0xff
+	}
+	for i := 0; i < len(sortableAlphabet); i++ {
+		table[sortableAlphabet[i]] = byte(i)
+		table[sortableAlphabet[i]|0x20] = byte(i)
+	}
+	// Crockford's aliases for commonly confused characters
+	for _, alias := range []struct{ from, to byte }{{'O', '0'}, {'I', '1'}, {'L', '1'}} {
+		table[alias.from] = table[alias.to]
+		table[alias.from|0x20] = table[alias.to]
+	}
+	return table
+}()
+
+func encodeSortable(value uint64) string {
+	var buffer [sortableLength]byte
+	for i := sortableLength - 1; i >= 0; i-- {
+		buffer[i] = sortableAlphabet[value&0x1f]
+		value >>= 5
+	}
+	return string(buffer[:])
+}
+
+func decodeSortable(str string) (uint64, error) {
+	if len(str) != sortableLength {
+		return 0, fmt.Errorf("%w: length %v (expected %v)", ErrInvalidSortable, len(str), sortableLength)
+	}
+	var value uint64
+	for i := 0; i < len(str); i++ {
+		digit := sortableDecodeTable[str[i]]
+		if digit == 0xff || (i == 0 && digit > 0xf) {
+			return 0, fmt.Errorf("%w: invalid character %q at index %v", ErrInvalidSortable, str[i], i)
+		}
+		value = value<<5 | uint64(digit)
+	}
+	return value, nil
+}
+
+// EncodeSortable encodes t as a 13 character string whose lexicographic order
+// matches the order of the values.
+func (t Smalltime) EncodeSortable() string {
+	return encodeSortable(uint64(t) ^ 1<<63)
+}
+
+func DecodeSortableSmalltime(str string) (Smalltime, error) {
+	value, err := decodeSortable(str)
+	return Smalltime(value ^ 1<<63), err
+}
+
+// EncodeSortable encodes t as a 13 character string whose lexicographic order
+// matches the order of the values.
+func (t Nanotime) EncodeSortable() string {
+	return encodeSortable(uint64(t))
+}
+
+func DecodeSortableNanotime(str string) (Nanotime, error) {
+	value, err := decodeSortable(str)
+	return Nanotime(value), err
+}
@@ -0,0 +1,84 @@
+package smalltime
+
+import "errors"
+import "math/rand"
+import "testing"
+
+func assertSortableRoundTrip(t *testing.T, value Smalltime) string {
+	encoded := value.EncodeSortable()
+	if len(encoded) != 13 {
+		t.Errorf("Expected %016x to encode to 13 characters but got %v", value, encoded)
+	}
+	decoded, err := DecodeSortableSmalltime(encoded)
+	if err != nil || decoded != value {
+		t.Errorf("Expected %v to decode to %016x but got %016x (%v)", encoded, value, decoded, err)
+	}
+	return encoded
+}
+
+func TestSortableExamples(t *testing.T) {
+	if encoded := assertSortableRoundTrip(t, Smalltime(-1<<63)); encoded != "0000000000000" {
+		t.Errorf("Expected the minimum value to encode to all zeroes but got %v", encoded)
+	}
+	if encoded := assertSortableRoundTrip(t, Smalltime(1<<63-1)); encoded != "FZZZZZZZZZZZZ" {
+		t.Errorf("Expected the maximum value to encode to FZZZZZZZZZZZZ but got %v", encoded)
+	}
+	if encoded := assertSortableRoundTrip(t, Smalltime(0x1f06b48590dbc2e)); encoded != "83W3B91CGVF1E" {
+		t.Errorf("Unexpected encoding %v", encoded)
+	}
+	if encoded := Nanotime(0).EncodeSortable(); encoded != "0000000000000" {
+		t.Errorf("Unexpected encoding %v", encoded)
+	}
+}
+
+func TestSortableOrderAcrossYearZero(t *testing.T) {
+	values := []Smalltime{
+		NewSmalltime(-131072, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(-1, 12, 31, 23, 59, 60, 999999),
+		NewSmalltime(0, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(1, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(131071, 12, 31, 23, 59, 60, 999999),
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i-1].EncodeSortable() >= values[i].EncodeSortable() {
+			t.Errorf("Expected %v < %v", values[i-1].EncodeSortable(), values[i].EncodeSortable())
+		}
+	}
+}
+
+func TestSortableProperties(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		a, b := Smalltime(random.Uint64()), Smalltime(random.Uint64())
+		encodedA, encodedB := assertSortableRoundTrip(t, a), assertSortableRoundTrip(t, b)
+		if (a < b) != (encodedA < encodedB) || (a == b) != (encodedA == encodedB) {
+			t.Fatalf("Order of %016x and %016x doesn't match order of %v and %v", a, b, encodedA, encodedB)
+		}
+
+		na, nb := Nanotime(random.Uint64()), Nanotime(random.Uint64())
+		encodedNA, encodedNB := na.EncodeSortable(), nb.EncodeSortable()
+		if (na < nb) != (encodedNA < encodedNB) {
+			t.Fatalf("Order of %016x and %016x doesn't match order of %v and %v", na, nb, encodedNA, encodedNB)
+		}
+		if decoded, err := DecodeSortableNanotime(encodedNA); err != nil || decoded != na {
+			t.Fatalf("Expected %v to decode to %016x but got %016x (%v)", encodedNA, na, decoded, err)
+		}
+	}
+}
+
+func TestSortableDecodeLeniency(t *testing.T) {
+	expected, _ := DecodeSortableSmalltime("83W3B91CGVF1E")
+	for _, alias := range []string{"83w3b91cgvf1e", "83W3B9ICGVFLE", "83W3B91CGVFlE"} {
+		if decoded, err := DecodeSortableSmalltime(alias); err != nil || decoded != expected {
+			t.Errorf("Expected %v to decode to %016x but got %016x (%v)", alias, expected, decoded, err)
+		}
+	}
+}
+
+func TestSortableInvalid(t *testing.T) {
+	for _, str := range []string{"", "83W3B91CGVF1", "83W3B91CGVF1EE", "G000000000000", "83W3B91CGVF1U", "83W3B91CG-F1E"} {
+		if _, err := DecodeSortableSmalltime(str); !errors.Is(err, ErrInvalidSortable) {
+			t.Errorf("Expected %q to be rejected with ErrInvalidSortable but got %v", str, err)
+		}
+	}
+}
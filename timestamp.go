@@ -0,0 +1,122 @@
+package smalltime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timestamp is implemented by the encoded types that carry a complete date &
+// time (Smalltime, Nanotime, and Smalltime48), allowing helpers to be written
+// once for all of them. The field accessors are meaningless for the infinity
+// sentinels, which helpers detect through Infinity.
+type Timestamp interface {
+	// Returns 1 for the positive infinity sentinel, -1 for the negative one,
+	// and 0 for all other values.
+	Infinity() int
+	Year() int
+	Month() int
+	Day() int
+	Hour() int
+	Minute() int
+	Second() int
+	SubsecondNanos() int
+	Doy() int
+	AsTime() time.Time
+}
+
+var _ Timestamp = Smalltime(0)
+var _ Timestamp = Nanotime(0)
+var _ Timestamp = Smalltime48(0)
+
+// Compare returns -1 if a is earlier than b, 1 if a is later than b, and 0 if
+// they are equal. Values are compared field by field, so a leap second sorts
+// between second 59 and the following minute.
+func Compare[T Timestamp](a, b T) int {
+	fields := [...][2]int{
+		{a.Year(), b.Year()},
+		{a.Month(), b.Month()},
+		{a.Day(), b.Day()},
+		{a.Hour(), b.Hour()},
+		{a.Minute(), b.Minute()},
+		{a.Second(), b.Second()},
+		{a.SubsecondNanos(), b.SubsecondNanos()},
+	}
+	for _, field := range fields {
+		if field[0] < field[1] {
+			return -1
+		}
+		if field[0] > field[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func Before[T Timestamp](a, b T) bool {
+	return Compare(a, b) < 0
+}
+
+func After[T Timestamp](a, b T) bool {
+	return Compare(a, b) > 0
+}
+
+// Sort sorts the values in chronological order.
+func Sort[T Timestamp](values []T) {
+	sort.Slice(values, func(i, j int) bool {
+		return Compare(values[i], values[j]) < 0
+	})
+}
+
+// Min returns the earliest of the supplied values.
+func Min[T Timestamp](first T, rest ...T) T {
+	min := first
+	for _, v := range rest {
+		if Compare(v, min) < 0 {
+			min = v
+		}
+	}
+	return min
+}
+
+// Max returns the latest of the supplied values.
+func Max[T Timestamp](first T, rest ...T) T {
+	max := first
+	for _, v := range rest {
+		if Compare(v, max) > 0 {
+			max = v
+		}
+	}
+	return max
+}
+
+// Format renders the value as an ISO 8601 UTC timestamp directly from its
+// fields (so a leap second renders as second 60). The fractional part is
+// omitted when zero, and has its trailing zeroes removed otherwise. Years
+// before 0000 or after 9999 are written in expanded form, such as -0044 or
+// +10000. The infinity sentinels render as "infinity" and "-infinity".
+func Format[T Timestamp](t T) string {
+	if str, ok := formatInfinity(t); ok {
+		return str
+	}
+	str := fmt.Sprintf("%v-%02d-%02dT%02d:%02d:%02d",
+		formatYear(t.Year()), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	if nanos := t.SubsecondNanos(); nanos != 0 {
+		str += strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0")
+	}
+	return str + "Z"
+}
+
+// Years outside of 0000-9999 use the ISO 8601 expanded representation: a sign
+// followed by at least 4 digits (-0044, +10000).
+func formatYear(year int) string {
+	switch {
+	case year < 0:
+		return fmt.Sprintf("-%04d", -year)
+	case year > 9999:
+		return fmt.Sprintf("+%d", year)
+	default:
+		return fmt.Sprintf("%04d", year)
+	}
+}
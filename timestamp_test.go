@@ -0,0 +1,90 @@
+package smalltime
+
+import "testing"
+
+func assertCompare[T Timestamp](t *testing.T, a, b T, expected int) {
+	if actual := Compare(a, b); actual != expected {
+		t.Errorf("Expected Compare(%v, %v) to be %v but got %v", Format(a), Format(b), expected, actual)
+	}
+}
+
+func assertFormat[T Timestamp](t *testing.T, value T, expected string) {
+	if actual := Format(value); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestCompareTimestamp(t *testing.T) {
+	assertCompare(t, NewSmalltime(2000, 1, 1, 0, 0, 0, 0), NewSmalltime(2000, 1, 1, 0, 0, 0, 0), 0)
+	assertCompare(t, NewSmalltime(2000, 1, 1, 0, 0, 0, 1), NewSmalltime(2000, 1, 1, 0, 0, 0, 0), 1)
+	assertCompare(t, NewSmalltime(-1, 12, 31, 0, 0, 0, 0), NewSmalltime(0, 1, 1, 0, 0, 0, 0), -1)
+	assertCompare(t, NewNanotime(2000, 1, 1, 0, 0, 60, 0), NewNanotime(2000, 1, 1, 0, 1, 0, 0), -1)
+	assertCompare(t, NewNanotime(2000, 1, 1, 0, 0, 0, 1), NewNanotime(2000, 1, 1, 0, 0, 0, 0), 1)
+	assertCompare(t, NewSmalltime48(2000, 1, 2, 0, 0, 0, 0), NewSmalltime48(2000, 1, 1, 23, 59, 59, 999), 1)
+}
+
+func TestCompareMatchesEncodedOrder(t *testing.T) {
+	values := []Smalltime{
+		NewSmalltime(-500, 3, 1, 0, 0, 0, 0),
+		NewSmalltime(-1, 12, 31, 23, 59, 59, 999999),
+		NewSmalltime(0, 1, 1, 0, 0, 0, 0),
+		NewSmalltime(1999, 2, 15, 12, 8, 45, 9122),
+		NewSmalltime(2000, 1, 1, 12, 0, 0, 0),
+		NewSmalltime(2000, 1, 1, 12, 0, 0, 1),
+	}
+	for i := 0; i < len(values); i++ {
+		for j := 0; j < len(values); j++ {
+			expected := 0
+			if values[i] < values[j] {
+				expected = -1
+			} else if values[i] > values[j] {
+				expected = 1
+			}
+			assertCompare(t, values[i], values[j], expected)
+		}
+	}
+}
+
+func TestSortTimestamp(t *testing.T) {
+	values := []Nanotime{
+		NewNanotime(2010, 5, 1, 0, 0, 0, 0),
+		NewNanotime(1985, 10, 26, 8, 22, 16, 123900142),
+		NewNanotime(2010, 4, 30, 23, 59, 60, 0),
+		NewNanotime(1985, 10, 26, 8, 22, 16, 123900141),
+	}
+	Sort(values)
+	for i := 1; i < len(values); i++ {
+		if values[i-1] > values[i] {
+			t.Errorf("Values not sorted at index %v: %v > %v", i, Format(values[i-1]), Format(values[i]))
+		}
+	}
+}
+
+func TestMinMaxTimestamp(t *testing.T) {
+	a := NewSmalltime(2000, 1, 1, 0, 0, 0, 0)
+	b := NewSmalltime(-44, 3, 15, 12, 0, 0, 0)
+	c := NewSmalltime(2000, 1, 1, 0, 0, 0, 1)
+	if min := Min(a, b, c); min != b {
+		t.Errorf("Expected min %v but got %v", Format(b), Format(min))
+	}
+	if max := Max(a, b, c); max != c {
+		t.Errorf("Expected max %v but got %v", Format(c), Format(max))
+	}
+	if min := Min(a); min != a {
+		t.Errorf("Expected min %v but got %v", Format(a), Format(min))
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	assertFormat(t, NewSmalltime(1999, 2, 15, 12, 8, 45, 9122), "1999-02-15T12:08:45.009122Z")
+	assertFormat(t, NewSmalltime(2000, 1, 1, 0, 0, 0, 0), "2000-01-01T00:00:00Z")
+	assertFormat(t, NewSmalltime(2016, 12, 31, 23, 59, 60, 500000), "2016-12-31T23:59:60.5Z")
+	assertFormat(t, NewNanotime(1999, 2, 15, 12, 8, 45, 10159122), "1999-02-15T12:08:45.010159122Z")
+	assertFormat(t, NewSmalltime48(2019, 5, 20, 1, 2, 3, 40), "2019-05-20T01:02:03.04Z")
+	assertFormat(t, NewSmalltime(-44, 3, 15, 12, 0, 0, 0), "-0044-03-15T12:00:00Z")
+	assertFormat(t, NewSmalltime(0, 1, 1, 0, 0, 0, 0), "0000-01-01T00:00:00Z")
+	assertFormat(t, NewSmalltime(9999, 12, 31, 23, 59, 59, 0), "9999-12-31T23:59:59Z")
+	assertFormat(t, NewSmalltime(10000, 1, 1, 0, 0, 0, 0), "+10000-01-01T00:00:00Z")
+	assertFormat(t, MinSmalltime, "-131072-01-01T00:00:00Z")
+	assertFormat(t, MaxSmalltime, "+131071-12-31T23:59:59.999999Z")
+}
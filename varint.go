@@ -0,0 +1,189 @@
+package smalltime
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Compact encodings for network protocols, where most values are close to a
+// known reference value:
+//
+// The varint form is the difference between the value and a reference value,
+// written as a zigzag varint (as per encoding/binary). The difference is
+// between the encoded fields, so it's only small when the higher fields match:
+// a smalltime in the same second as its reference takes at most 3 bytes (5 for
+// nanotime), but crossing into another minute, hour or day makes it much
+// larger.
+//
+// The prefix form is a header byte containing the number of leading bytes
+// (0-8) that the big-endian raw value shares with the previous value, followed
+// by the remaining bytes.
+
+var ErrVarintOverflow = errors.New("smalltime: varint overflows 64 bits")
+var ErrInvalidPrefix = errors.New("smalltime: invalid common-prefix header")
+
+func appendVarint(dst []byte, delta int64) []byte {
+	var buffer [binary.MaxVarintLen64]byte
+	return append(dst, buffer[:binary.PutVarint(buffer[:], delta)]...)
+}
+
+func decodeVarint(src []byte) (int64, int, error) {
+	delta, n := binary.Varint(src)
+	if n == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if n < 0 {
+		return 0, -n, ErrVarintOverflow
+	}
+	return delta, n, nil
+}
+
+// Records errors from the underlying reader, to tell them apart from
+// binary.ReadVarint's own overflow error.
+type errorRecordingReader struct {
+	io.ByteReader
+	err error
+}
+
+func (r *errorRecordingReader) ReadByte() (byte, error) {
+	b, err := r.ByteReader.ReadByte()
+	if err != nil {
+		r.err = err
+	}
+	return b, err
+}
+
+func readVarint(r io.ByteReader) (int64, error) {
+	reader := errorRecordingReader{ByteReader: r}
+	delta, err := binary.ReadVarint(&reader)
+	if err != nil && reader.err == nil {
+		return 0, ErrVarintOverflow
+	}
+	return delta, err
+}
+
+func appendPrefix(dst []byte, value, previous uint64) []byte {
+	shared := bits.LeadingZeros64(value^previous) / 8
+	dst = append(dst, byte(shared))
+	for i := shared; i < 8; i++ {
+		dst = append(dst, byte(value>>(56-8*i)))
+	}
+	return dst
+}
+
+func decodePrefix(src []byte, previous uint64) (uint64, int, error) {
+	if len(src) == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	shared := int(src[0])
+	if shared > 8 {
+		return 0, 1, ErrInvalidPrefix
+	}
+	length := 1 + 8 - shared
+	if len(src) < length {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	value := previous
+	for i := shared; i < 8; i++ {
+		shift := 56 - 8*i
+		value = value&^(0xff<<shift) | uint64(src[1+i-shared])<<shift
+	}
+	return value, length, nil
+}
+
+func readPrefix(r io.ByteReader, previous uint64) (uint64, error) {
+	var buffer [9]byte
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	buffer[0] = b
+	if b > 8 {
+		return 0, ErrInvalidPrefix
+	}
+	length := 1 + 8 - int(b)
+	for i := 1; i < length; i++ {
+		if buffer[i], err = r.ReadByte(); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+	}
+	value, _, err := decodePrefix(buffer[:length], previous)
+	return value, err
+}
+
+// AppendVarint appends the difference between t and reference as a zigzag
+// varint.
+func (t Smalltime) AppendVarint(dst []byte, reference Smalltime) []byte {
+	return appendVarint(dst, int64(t-reference))
+}
+
+// DecodeSmalltimeVarint decodes a value written by Smalltime.AppendVarint,
+// returning it along with the number of bytes consumed.
+func DecodeSmalltimeVarint(src []byte, reference Smalltime) (Smalltime, int, error) {
+	delta, n, err := decodeVarint(src)
+	return reference + Smalltime(delta), n, err
+}
+
+func ReadSmalltimeVarint(r io.ByteReader, reference Smalltime) (Smalltime, error) {
+	delta, err := readVarint(r)
+	return reference + Smalltime(delta), err
+}
+
+// AppendPrefix appends t in common-prefix form, omitting the leading bytes
+// it shares with previous.
+func (t Smalltime) AppendPrefix(dst []byte, previous Smalltime) []byte {
+	return appendPrefix(dst, uint64(t), uint64(previous))
+}
+
+// DecodeSmalltimePrefix decodes a value written by Smalltime.AppendPrefix,
+// returning it along with the number of bytes consumed.
+func DecodeSmalltimePrefix(src []byte, previous Smalltime) (Smalltime, int, error) {
+	value, n, err := decodePrefix(src, uint64(previous))
+	return Smalltime(value), n, err
+}
+
+func ReadSmalltimePrefix(r io.ByteReader, previous Smalltime) (Smalltime, error) {
+	value, err := readPrefix(r, uint64(previous))
+	return Smalltime(value), err
+}
+
+// AppendVarint appends the difference between t and reference as a zigzag
+// varint.
+func (t Nanotime) AppendVarint(dst []byte, reference Nanotime) []byte {
+	return appendVarint(dst, int64(t-reference))
+}
+
+// DecodeNanotimeVarint decodes a value written by Nanotime.AppendVarint,
+// returning it along with the number of bytes consumed.
+func DecodeNanotimeVarint(src []byte, reference Nanotime) (Nanotime, int, error) {
+	delta, n, err := decodeVarint(src)
+	return reference + Nanotime(delta), n, err
+}
+
+func ReadNanotimeVarint(r io.ByteReader, reference Nanotime) (Nanotime, error) {
+	delta, err := readVarint(r)
+	return reference + Nanotime(delta), err
+}
+
+// AppendPrefix appends t in common-prefix form, omitting the leading bytes
+// it shares with previous.
+func (t Nanotime) AppendPrefix(dst []byte, previous Nanotime) []byte {
+	return appendPrefix(dst, uint64(t), uint64(previous))
+}
+
+// DecodeNanotimePrefix decodes a value written by Nanotime.AppendPrefix,
+// returning it along with the number of bytes consumed.
+func DecodeNanotimePrefix(src []byte, previous Nanotime) (Nanotime, int, error) {
+	value, n, err := decodePrefix(src, uint64(previous))
+	return Nanotime(value), n, err
+}
+
+func ReadNanotimePrefix(r io.ByteReader, previous Nanotime) (Nanotime, error) {
+	value, err := readPrefix(r, uint64(previous))
+	return Nanotime(value), err
+}
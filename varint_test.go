@@ -0,0 +1,180 @@
+package smalltime
+
+import "bufio"
+import "bytes"
+import "io"
+import "math/rand"
+import "testing"
+
+func assertVarintRoundTrip(t *testing.T, value, reference Smalltime) []byte {
+	encoded := value.AppendVarint(nil, reference)
+	decoded, n, err := DecodeSmalltimeVarint(encoded, reference)
+	if err != nil || n != len(encoded) || decoded != value {
+		t.Errorf("Expected %016x (ref %016x) to decode from %x, but got %016x, %v, %v", value, reference, encoded, decoded, n, err)
+	}
+	decoded, err = ReadSmalltimeVarint(bytes.NewReader(encoded), reference)
+	if err != nil || decoded != value {
+		t.Errorf("Expected %016x (ref %016x) to read from %x, but got %016x, %v", value, reference, encoded, decoded, err)
+	}
+	return encoded
+}
+
+func assertPrefixRoundTrip(t *testing.T, value, previous Smalltime) []byte {
+	encoded := value.AppendPrefix(nil, previous)
+	decoded, n, err := DecodeSmalltimePrefix(encoded, previous)
+	if err != nil || n != len(encoded) || decoded != value {
+		t.Errorf("Expected %016x (prev %016x) to decode from %x, but got %016x, %v, %v", value, previous, encoded, decoded, n, err)
+	}
+	decoded, err = ReadSmalltimePrefix(bytes.NewReader(encoded), previous)
+	if err != nil || decoded != value {
+		t.Errorf("Expected %016x (prev %016x) to read from %x, but got %016x, %v", value, previous, encoded, decoded, err)
+	}
+	return encoded
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	reference := NewSmalltime(2019, 5, 20, 12, 0, 0, 0)
+	assertVarintRoundTrip(t, reference, reference)
+	assertVarintRoundTrip(t, NewSmalltime(-131072, 1, 1, 0, 0, 0, 0), reference)
+	assertVarintRoundTrip(t, NewSmalltime(131071, 12, 31, 23, 59, 60, 999999), NewSmalltime(-131072, 1, 1, 0, 0, 0, 0))
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		assertVarintRoundTrip(t, Smalltime(random.Uint64()), Smalltime(random.Uint64()))
+	}
+
+	if encoded := assertVarintRoundTrip(t, NewSmalltime(2019, 5, 20, 12, 0, 0, 999999), reference); len(encoded) > 3 {
+		t.Errorf("Expected a value in the same second as the reference to encode to at most 3 bytes, but got %x", encoded)
+	}
+}
+
+func TestVarintRoundTripNanotime(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		value, reference := Nanotime(random.Uint64()), Nanotime(random.Uint64())
+		encoded := value.AppendVarint(nil, reference)
+		decoded, n, err := DecodeNanotimeVarint(encoded, reference)
+		if err != nil || n != len(encoded) || decoded != value {
+			t.Errorf("Expected %016x (ref %016x) to decode from %x, but got %016x, %v, %v", value, reference, encoded, decoded, n, err)
+		}
+		decoded, err = ReadNanotimeVarint(bytes.NewReader(encoded), reference)
+		if err != nil || decoded != value {
+			t.Errorf("Expected %016x (ref %016x) to read from %x, but got %016x, %v", value, reference, encoded, decoded, err)
+		}
+	}
+}
+
+func TestPrefixRoundTrip(t *testing.T) {
+	previous := NewSmalltime(2019, 5, 20, 12, 0, 0, 0)
+	if encoded := assertPrefixRoundTrip(t, previous, previous); len(encoded) != 1 {
+		t.Errorf("Expected an identical value to encode to 1 byte but got %x", encoded)
+	}
+	if encoded := assertPrefixRoundTrip(t, NewSmalltime(2019, 5, 20, 12, 0, 0, 5), previous); len(encoded) != 2 {
+		t.Errorf("Expected a value differing in the low byte to encode to 2 bytes but got %x", encoded)
+	}
+	assertPrefixRoundTrip(t, NewSmalltime(-1, 1, 1, 0, 0, 0, 0), previous)
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		assertPrefixRoundTrip(t, Smalltime(random.Uint64()), Smalltime(random.Uint64()))
+	}
+}
+
+func TestPrefixRoundTripNanotime(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		value, previous := Nanotime(random.Uint64()), Nanotime(random.Uint64())
+		encoded := value.AppendPrefix(nil, previous)
+		decoded, n, err := DecodeNanotimePrefix(encoded, previous)
+		if err != nil || n != len(encoded) || decoded != value {
+			t.Errorf("Expected %016x (prev %016x) to decode from %x, but got %016x, %v, %v", value, previous, encoded, decoded, n, err)
+		}
+		decoded, err = ReadNanotimePrefix(bytes.NewReader(encoded), previous)
+		if err != nil || decoded != value {
+			t.Errorf("Expected %016x (prev %016x) to read from %x, but got %016x, %v", value, previous, encoded, decoded, err)
+		}
+	}
+}
+
+// Ordering property: moving a value closer to the reference never makes its
+// encoding longer.
+func TestEncodedLengthOrdering(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	reference := NewSmalltime(2019, 5, 20, 12, 0, 0, 0)
+	for i := 0; i < 10000; i++ {
+		far := reference + Smalltime(random.Int63n(1<<uint(random.Intn(62)+1)))
+		near := reference + (far-reference)/2
+		if len(near.AppendVarint(nil, reference)) > len(far.AppendVarint(nil, reference)) {
+			t.Errorf("Varint of %016x is longer than that of %016x", near, far)
+		}
+		sharedNear := len(near.AppendPrefix(nil, reference))
+		sharedFar := len(far.AppendPrefix(nil, reference))
+		if near^reference <= far^reference && sharedNear > sharedFar {
+			t.Errorf("Prefix encoding of %016x is longer than that of %016x", near, far)
+		}
+	}
+}
+
+// Ordering property: a sorted series survives a chained encode/decode in order.
+func TestChainedStreamPreservesOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	values := make([]Smalltime, 1000)
+	current := NewSmalltime(2019, 5, 20, 12, 0, 0, 0)
+	for i := range values {
+		current += Smalltime(random.Int63n(1 << 30))
+		values[i] = current
+	}
+
+	var buffer []byte
+	previous := Smalltime(0)
+	for _, v := range values {
+		buffer = v.AppendPrefix(buffer, previous)
+		buffer = v.AppendVarint(buffer, previous)
+		previous = v
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(buffer))
+	previous = 0
+	for i, expected := range values {
+		fromPrefix, err := ReadSmalltimePrefix(reader, previous)
+		if err != nil {
+			t.Fatalf("Unexpected error at value %v: %v", i, err)
+		}
+		fromVarint, err := ReadSmalltimeVarint(reader, previous)
+		if err != nil {
+			t.Fatalf("Unexpected error at value %v: %v", i, err)
+		}
+		if fromPrefix != expected || fromVarint != expected || fromPrefix < previous {
+			t.Fatalf("Value %v: Expected %016x but got %016x / %016x", i, expected, fromPrefix, fromVarint)
+		}
+		previous = expected
+	}
+	if _, err := ReadSmalltimePrefix(reader, previous); err != io.EOF {
+		t.Errorf("Expected io.EOF but got %v", err)
+	}
+}
+
+func TestMalformedCompactEncodings(t *testing.T) {
+	if _, _, err := DecodeSmalltimeVarint([]byte{0x80, 0x80}, 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF but got %v", err)
+	}
+	overflow := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	if _, _, err := DecodeSmalltimeVarint(overflow, 0); err != ErrVarintOverflow {
+		t.Errorf("Expected ErrVarintOverflow but got %v", err)
+	}
+	if _, err := ReadSmalltimeVarint(bytes.NewReader(overflow), 0); err != ErrVarintOverflow {
+		t.Errorf("Expected ErrVarintOverflow but got %v", err)
+	}
+	if _, err := ReadSmalltimeVarint(bytes.NewReader([]byte{0x80}), 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF but got %v", err)
+	}
+	if _, _, err := DecodeSmalltimePrefix([]byte{9}, 0); err != ErrInvalidPrefix {
+		t.Errorf("Expected ErrInvalidPrefix but got %v", err)
+	}
+	if _, _, err := DecodeSmalltimePrefix([]byte{6, 1}, 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF but got %v", err)
+	}
+	if _, err := ReadSmalltimePrefix(bytes.NewReader([]byte{6, 1}), 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF but got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package smalltime
+
+//go:generate go run ./cmd/smalltime-vectors -o testdata/vectors.json
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type testVector struct {
+	Description string
+	Format      string
+	Raw         string
+	Year        int
+	Month       int
+	Day         int
+	Hour        int
+	Minute      int
+	Second      int
+	Subsecond   int
+	Valid       bool
+	ISO8601     string
+}
+
+func loadTestVectors(tb testing.TB) []testVector {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var file struct{ Vectors []testVector }
+	if err = json.Unmarshal(data, &file); err != nil {
+		tb.Fatal(err)
+	}
+	if len(file.Vectors) == 0 {
+		tb.Fatal("No test vectors found")
+	}
+	return file.Vectors
+}
+
+func (v testVector) fields() []int {
+	return []int{v.Year, v.Month, v.Day, v.Hour, v.Minute, v.Second, v.Subsecond}
+}
+
+func checkVector[T interface {
+	Timestamp
+	comparable
+}](t *testing.T, v testVector, value T, fields []int, encoded T, parse func(string) (T, error), validateErr error) {
+	for i, expected := range v.fields() {
+		if fields[i] != expected {
+			t.Errorf("%v %v (%v): expected decoded fields %v but got %v", v.Format, v.Raw, v.Description, v.fields(), fields)
+			break
+		}
+	}
+	if encoded != value {
+		t.Errorf("%v %v (%v): fields encoded to a different value", v.Format, v.Raw, v.Description)
+	}
+	if valid := validateErr == nil; valid != v.Valid {
+		t.Errorf("%v %v (%v): expected valid=%v but got %v", v.Format, v.Raw, v.Description, v.Valid, validateErr)
+	}
+	if !v.Valid {
+		return
+	}
+	if actual := Format(value); actual != v.ISO8601 {
+		t.Errorf("%v %v (%v): expected ISO 8601 %v but got %v", v.Format, v.Raw, v.Description, v.ISO8601, actual)
+	}
+	if parsed, err := parse(v.ISO8601); err != nil || parsed != value {
+		t.Errorf("%v %v (%v): %v parsed to %v (error %v)", v.Format, v.Raw, v.Description, v.ISO8601, Format(parsed), err)
+	}
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range loadTestVectors(t) {
+		raw, err := strconv.ParseUint(v.Raw, 0, 64)
+		if err != nil {
+			t.Errorf("%v: %v", v.Description, err)
+			continue
+		}
+		switch v.Format {
+		case "smalltime":
+			value := Smalltime(raw)
+			fields := []int{value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Microsecond()}
+			encoded := NewSmalltime(v.Year, v.Month, v.Day, v.Hour, v.Minute, v.Second, v.Subsecond)
+			checkVector(t, v, value, fields, encoded, ParseSmalltime, value.Validate())
+		case "nanotime":
+			value := Nanotime(raw)
+			fields := []int{value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Nanosecond()}
+			encoded := NewNanotime(v.Year, v.Month, v.Day, v.Hour, v.Minute, v.Second, v.Subsecond)
+			checkVector(t, v, value, fields, encoded, ParseNanotime, value.Validate())
+		default:
+			t.Errorf("%v: unknown format %v", v.Description, v.Format)
+		}
+	}
+}
+
+// Checks the vectors against the specifications' bit layouts and the time
+// package rather than this implementation, since the generator uses it too.
+func TestVectorsMatchSpecification(t *testing.T) {
+	for _, v := range loadTestVectors(t) {
+		raw, err := strconv.ParseUint(v.Raw, 0, 64)
+		if err != nil {
+			t.Errorf("%v: %v", v.Description, err)
+			continue
+		}
+		var expectedRaw uint64
+		nanos := v.Subsecond
+		switch v.Format {
+		case "smalltime":
+			expectedRaw = uint64(int64(v.Year))<<46 | uint64(v.Month)<<42 | uint64(v.Day)<<37 |
+				uint64(v.Hour)<<32 | uint64(v.Minute)<<26 | uint64(v.Second)<<20 | uint64(v.Subsecond)
+			nanos *= 1000
+		case "nanotime":
+			expectedRaw = uint64(v.Year-1970)<<56 | uint64(v.Month)<<52 | uint64(v.Day)<<47 |
+				uint64(v.Hour)<<42 | uint64(v.Minute)<<36 | uint64(v.Second)<<30 | uint64(v.Subsecond)
+		}
+		if raw != expectedRaw {
+			t.Errorf("%v %v (%v): expected raw 0x%016x from the specification", v.Format, v.Raw, v.Description, expectedRaw)
+		}
+
+		// The time package normalizes out of range fields, and has no leap seconds.
+		second := v.Second
+		if second == 60 {
+			second = 59
+		}
+		if v.Year < 0 || v.Year > 9999 || nanos > 999999999 {
+			continue
+		}
+		gotime := time.Date(v.Year, time.Month(v.Month), v.Day, v.Hour, v.Minute, second, nanos, time.UTC)
+		valid := gotime.Year() == v.Year && int(gotime.Month()) == v.Month && gotime.Day() == v.Day &&
+			gotime.Hour() == v.Hour && gotime.Minute() == v.Minute && gotime.Second() == second
+		if valid != v.Valid {
+			t.Errorf("%v %v (%v): expected valid=%v but the time package disagrees", v.Format, v.Raw, v.Description, v.Valid)
+		}
+		if valid && v.Second != 60 {
+			if expected := gotime.Format(time.RFC3339Nano); expected != v.ISO8601 {
+				t.Errorf("%v %v (%v): expected ISO 8601 %v but the time package gives %v", v.Format, v.Raw, v.Description, v.ISO8601, expected)
+			}
+		}
+	}
+}